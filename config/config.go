@@ -17,17 +17,74 @@ const (
 	APP_ENVIRONMENT_PROD  = "prod"
 )
 
+const (
+	STORAGE_BACKEND_AZBLOB = "azblob"
+	STORAGE_BACKEND_LOCAL  = "local"
+	STORAGE_BACKEND_S3     = "s3"
+)
+
+const (
+	TRASH_DEFAULT_RETENTION_DAYS = 30
+	TRASH_DEFAULT_PURGE_INTERVAL = 60
+)
+
+const (
+	LIFETIME_DEFAULT_SWEEP_INTERVAL = 5
+)
+
+const (
+	UPLOAD_DEFAULT_PENDING_TTL      = 1440
+	UPLOAD_DEFAULT_CLEANUP_INTERVAL = 30
+	UPLOAD_DEFAULT_PRESIGN_EXPIRE   = 15
+	UPLOAD_DEFAULT_STREAM_BLOCK_MB  = 8
+	UPLOAD_DEFAULT_STREAM_PARALLEL  = 2
+	UPLOAD_DEFAULT_STREAM_MAX       = 8
+)
+
+const (
+	SECRET_DEFAULT_ACCESS_TOKEN_TTL  = 15
+	SECRET_DEFAULT_REFRESH_TOKEN_TTL = 43200
+)
+
+const (
+	LOCKOUT_DEFAULT_MAX_ATTEMPTS    = 5
+	LOCKOUT_DEFAULT_IP_MAX_ATTEMPTS = 20
+	LOCKOUT_DEFAULT_BASE_DELAY      = 1
+	LOCKOUT_DEFAULT_MAX_DELAY       = 60
+	LOCKOUT_DEFAULT_DURATION        = 15
+	LOCKOUT_DEFAULT_WINDOW          = 60
+)
+
+const (
+	SCANNER_BACKEND_CLAMD = "clamd"
+)
+
+const (
+	SCANNER_INFECTED_ACTION_REJECT     = "reject"
+	SCANNER_INFECTED_ACTION_QUARANTINE = "quarantine"
+)
+
+const (
+	SCANNER_DEFAULT_TIMEOUT = 60
+)
+
 type Config struct {
 	App      AppConfig
 	Log      log.Config
 	Mongo    MongoConfig
 	AzAd     AzAdConfig
 	AzBlob   AzBlobConfig
+	S3       S3Config
 	Storage  StorageConfig
 	Cors     CorsConfig
 	Secret   SecretConfig
 	Upload   UploadConfig
 	Download DownloadConfig
+	Trash    TrashConfig
+	LifeTime LifeTimeConfig
+	Scanner  ScannerConfig
+	Auth     AuthConfig
+	Lockout  LockoutConfig
 }
 
 type AppConfig struct {
@@ -36,6 +93,8 @@ type AppConfig struct {
 	Port            string
 	Host            string
 	ShutdownTimeout int
+	TrustedProxies  []string // ips or cidrs allowed to set X-Forwarded-For, none by default
+	TrustedPlatform string   // header set by the platform with the client ip, e.g. CF-Connecting-IP
 }
 
 type CorsConfig struct {
@@ -55,6 +114,14 @@ type AzBlobConfig struct {
 	AccountKey  string
 }
 
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
 type AzAdConfig struct {
 	TenantId     string
 	ClientId     string
@@ -62,21 +129,79 @@ type AzAdConfig struct {
 }
 
 type StorageConfig struct {
+	Backend   string
 	Container string
+	LocalPath string
 }
 
 type SecretConfig struct {
-	SecretKey string
+	SecretKey       string
+	TokenKey        string // signs access tokens, must differ from the secret key
+	AccessTokenTTL  int64  // in minutes
+	RefreshTokenTTL int64  // in minutes
 }
 
 type UploadConfig struct {
-	MaxSizeMB int64
+	MaxSizeMB       int64
+	PendingTTL      int64 // in minutes
+	CleanupInterval int64 // in minutes
+	URLServerFetch  bool  // fetch url uploads through medioa instead of a backend copy
+	PresignExpire   int64 // in minutes
+	StreamBlockMB   int64 // block (part) size used to relay streams to storage
+	StreamParallel  int64 // blocks in flight per stream
+	StreamMax       int64 // streams relayed at once, memory is about StreamBlockMB * StreamParallel * StreamMax
+	Policy          UploadPolicyConfig
+}
+
+// UploadPolicyConfig is evaluated on the sniffed content of an upload,
+// types are mime families (image) or mime types (image/png), extensions have no leading dot.
+type UploadPolicyConfig struct {
+	AllowTypes    []string // empty allows all
+	DenyTypes     []string
+	AllowExts     []string // empty allows all
+	DenyExts      []string
+	TypeMaxSizeMB map[string]int64 // by mime family or mime type
 }
 
 type DownloadConfig struct {
 	Expire int64 // in days
 }
 
+type TrashConfig struct {
+	RetentionDays int64
+	PurgeInterval int64 // in minutes
+}
+
+type LifeTimeConfig struct {
+	SweepInterval int64 // in minutes
+}
+
+// ScannerConfig configures malware scanning of uploads, an empty backend disables scanning.
+type ScannerConfig struct {
+	Backend        string
+	Address        string // tcp://host:port or unix:///path/to/clamd.sock
+	Timeout        int64  // in seconds
+	Enforce        bool   // only files marked clean can be downloaded
+	InfectedAction string // reject or quarantine
+}
+
+// AuthConfig configures how callers are identified, tokens map a static token to its user id.
+type AuthConfig struct {
+	Required bool // reject anonymous callers, share links still work without auth. Defaults to true once tokens are set
+	Tokens   map[string]int64
+}
+
+// LockoutConfig limits guessing of secret passwords, pin codes and download passwords,
+// failures are counted per account and per client ip.
+type LockoutConfig struct {
+	MaxAttempts   int64 // per account before lockout
+	IPMaxAttempts int64 // per client ip before lockout
+	BaseDelay     int64 // in seconds, doubled on every failure of an account
+	MaxDelay      int64 // in seconds
+	Duration      int64 // in minutes
+	Window        int64 // in minutes, failures older than it are forgotten
+}
+
 func Load() (*Config, error) {
 	if _, err := os.Stat(".env"); err == nil {
 		err := godotenv.Load()
@@ -90,12 +215,18 @@ func Load() (*Config, error) {
 	parseLogConfig(cfg)
 	parseMongoConfig(cfg)
 	parseAzBlobConfig(cfg)
+	parseS3Config(cfg)
 	parseStorageConfig(cfg)
 	parseCorsConfig(cfg)
 	parseAzAdConfig(cfg)
 	parseSecretConfig(cfg)
 	parseUploadConfig(cfg)
 	parseDownloadConfig(cfg)
+	parseTrashConfig(cfg)
+	parseLifeTimeConfig(cfg)
+	parseScannerConfig(cfg)
+	parseAuthConfig(cfg)
+	parseLockoutConfig(cfg)
 
 	return cfg, validation(cfg)
 }
@@ -112,6 +243,13 @@ func parseAppConfig(cfg *Config) {
 	cfg.App.Host = os.Getenv("HOST")
 	shutdownTimeout, _ := strconv.Atoi(os.Getenv("SHUTDOWN_TIMEOUT"))
 	cfg.App.ShutdownTimeout = shutdownTimeout
+	cfg.App.TrustedProxies = []string{}
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			cfg.App.TrustedProxies = append(cfg.App.TrustedProxies, proxy)
+		}
+	}
+	cfg.App.TrustedPlatform = os.Getenv("TRUSTED_PLATFORM")
 }
 
 func parseMongoConfig(cfg *Config) {
@@ -125,8 +263,22 @@ func parseAzBlobConfig(cfg *Config) {
 	cfg.AzBlob.AccountKey = os.Getenv("AZBLOB_ACCOUNT_KEY")
 }
 
+func parseS3Config(cfg *Config) {
+	cfg.S3.Endpoint = os.Getenv("S3_ENDPOINT")
+	cfg.S3.Region = os.Getenv("S3_REGION")
+	cfg.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	useSSL, _ := strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+	cfg.S3.UseSSL = useSSL
+}
+
 func parseStorageConfig(cfg *Config) {
+	cfg.Storage.Backend = os.Getenv("STORAGE_BACKEND")
+	if cfg.Storage.Backend == "" {
+		cfg.Storage.Backend = STORAGE_BACKEND_AZBLOB
+	}
 	cfg.Storage.Container = os.Getenv("STORAGE_CONTAINER")
+	cfg.Storage.LocalPath = os.Getenv("STORAGE_LOCAL_PATH")
 }
 
 func parseCorsConfig(cfg *Config) {
@@ -146,11 +298,78 @@ func parseSecretConfig(cfg *Config) {
 	if secretKey != "" {
 		cfg.Secret.SecretKey = cryp.HashMD5(secretKey)
 	}
+	cfg.Secret.TokenKey = os.Getenv("SECRET_TOKEN_KEY")
+	accessTokenTTL, err := strconv.ParseInt(os.Getenv("SECRET_ACCESS_TOKEN_TTL"), 10, 64)
+	if err != nil {
+		accessTokenTTL = SECRET_DEFAULT_ACCESS_TOKEN_TTL
+	}
+	cfg.Secret.AccessTokenTTL = accessTokenTTL
+	refreshTokenTTL, err := strconv.ParseInt(os.Getenv("SECRET_REFRESH_TOKEN_TTL"), 10, 64)
+	if err != nil {
+		refreshTokenTTL = SECRET_DEFAULT_REFRESH_TOKEN_TTL
+	}
+	cfg.Secret.RefreshTokenTTL = refreshTokenTTL
 }
 
 func parseUploadConfig(cfg *Config) {
 	maxSizeMB, _ := strconv.ParseInt(os.Getenv("UPLOAD_MAX_SIZE_MB"), 10, 64)
 	cfg.Upload.MaxSizeMB = maxSizeMB
+	pendingTTL, err := strconv.ParseInt(os.Getenv("UPLOAD_PENDING_TTL"), 10, 64)
+	if err != nil {
+		pendingTTL = UPLOAD_DEFAULT_PENDING_TTL
+	}
+	cfg.Upload.PendingTTL = pendingTTL
+	cleanupInterval, err := strconv.ParseInt(os.Getenv("UPLOAD_CLEANUP_INTERVAL"), 10, 64)
+	if err != nil {
+		cleanupInterval = UPLOAD_DEFAULT_CLEANUP_INTERVAL
+	}
+	cfg.Upload.CleanupInterval = cleanupInterval
+	presignExpire, err := strconv.ParseInt(os.Getenv("UPLOAD_PRESIGN_EXPIRE"), 10, 64)
+	if err != nil {
+		presignExpire = UPLOAD_DEFAULT_PRESIGN_EXPIRE
+	}
+	cfg.Upload.PresignExpire = presignExpire
+	streamBlockMB, err := strconv.ParseInt(os.Getenv("UPLOAD_STREAM_BLOCK_MB"), 10, 64)
+	if err != nil {
+		streamBlockMB = UPLOAD_DEFAULT_STREAM_BLOCK_MB
+	}
+	cfg.Upload.StreamBlockMB = streamBlockMB
+	streamParallel, err := strconv.ParseInt(os.Getenv("UPLOAD_STREAM_PARALLEL"), 10, 64)
+	if err != nil {
+		streamParallel = UPLOAD_DEFAULT_STREAM_PARALLEL
+	}
+	cfg.Upload.StreamParallel = streamParallel
+	streamMax, err := strconv.ParseInt(os.Getenv("UPLOAD_STREAM_MAX"), 10, 64)
+	if err != nil {
+		streamMax = UPLOAD_DEFAULT_STREAM_MAX
+	}
+	cfg.Upload.StreamMax = streamMax
+	urlServerFetch, _ := strconv.ParseBool(os.Getenv("UPLOAD_URL_SERVER_FETCH"))
+	cfg.Upload.URLServerFetch = urlServerFetch
+	cfg.Upload.Policy.AllowTypes = parseList(os.Getenv("UPLOAD_ALLOW_TYPES"))
+	cfg.Upload.Policy.DenyTypes = parseList(os.Getenv("UPLOAD_DENY_TYPES"))
+	cfg.Upload.Policy.AllowExts = parseList(os.Getenv("UPLOAD_ALLOW_EXTS"))
+	cfg.Upload.Policy.DenyExts = parseList(os.Getenv("UPLOAD_DENY_EXTS"))
+
+	// format: image:10,video/mp4:500
+	cfg.Upload.Policy.TypeMaxSizeMB = make(map[string]int64)
+	for _, item := range parseList(os.Getenv("UPLOAD_TYPE_MAX_SIZE_MB")) {
+		mimeType, maxSizeStr, _ := strings.Cut(item, ":")
+		maxSizeMB, _ := strconv.ParseInt(strings.TrimSpace(maxSizeStr), 10, 64)
+		cfg.Upload.Policy.TypeMaxSizeMB[strings.TrimSpace(mimeType)] = maxSizeMB
+	}
+}
+
+// parseList splits a comma separated value, items are trimmed and lower cased
+func parseList(value string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
 func parseDownloadConfig(cfg *Config) {
@@ -158,6 +377,97 @@ func parseDownloadConfig(cfg *Config) {
 	cfg.Download.Expire = expire
 }
 
+func parseTrashConfig(cfg *Config) {
+	retentionDays, err := strconv.ParseInt(os.Getenv("TRASH_RETENTION_DAYS"), 10, 64)
+	if err != nil {
+		retentionDays = TRASH_DEFAULT_RETENTION_DAYS
+	}
+	cfg.Trash.RetentionDays = retentionDays
+	purgeInterval, err := strconv.ParseInt(os.Getenv("TRASH_PURGE_INTERVAL"), 10, 64)
+	if err != nil {
+		purgeInterval = TRASH_DEFAULT_PURGE_INTERVAL
+	}
+	cfg.Trash.PurgeInterval = purgeInterval
+}
+
+func parseLifeTimeConfig(cfg *Config) {
+	sweepInterval, err := strconv.ParseInt(os.Getenv("LIFETIME_SWEEP_INTERVAL"), 10, 64)
+	if err != nil {
+		sweepInterval = LIFETIME_DEFAULT_SWEEP_INTERVAL
+	}
+	cfg.LifeTime.SweepInterval = sweepInterval
+}
+
+func parseScannerConfig(cfg *Config) {
+	cfg.Scanner.Backend = os.Getenv("SCANNER_BACKEND")
+	cfg.Scanner.Address = os.Getenv("SCANNER_ADDRESS")
+	timeout, err := strconv.ParseInt(os.Getenv("SCANNER_TIMEOUT"), 10, 64)
+	if err != nil {
+		timeout = SCANNER_DEFAULT_TIMEOUT
+	}
+	cfg.Scanner.Timeout = timeout
+	enforce, _ := strconv.ParseBool(os.Getenv("SCANNER_ENFORCE"))
+	cfg.Scanner.Enforce = enforce
+	cfg.Scanner.InfectedAction = os.Getenv("SCANNER_INFECTED_ACTION")
+	if cfg.Scanner.InfectedAction == "" {
+		cfg.Scanner.InfectedAction = SCANNER_INFECTED_ACTION_REJECT
+	}
+}
+
+func parseAuthConfig(cfg *Config) {
+	// format: token:userId,token:userId, tokens are case sensitive
+	cfg.Auth.Tokens = make(map[string]int64)
+	for _, item := range strings.Split(os.Getenv("AUTH_TOKENS"), ",") {
+		token, userIdStr, _ := strings.Cut(strings.TrimSpace(item), ":")
+		if token == "" {
+			continue
+		}
+		userId, _ := strconv.ParseInt(strings.TrimSpace(userIdStr), 10, 64)
+		cfg.Auth.Tokens[token] = userId
+	}
+
+	// once tokens exist anonymous callers would skip every scope, so auth is
+	// required unless it is turned off explicitly
+	required, err := strconv.ParseBool(os.Getenv("AUTH_REQUIRED"))
+	if err != nil {
+		required = len(cfg.Auth.Tokens) > 0
+	}
+	cfg.Auth.Required = required
+}
+
+func parseLockoutConfig(cfg *Config) {
+	maxAttempts, err := strconv.ParseInt(os.Getenv("LOCKOUT_MAX_ATTEMPTS"), 10, 64)
+	if err != nil {
+		maxAttempts = LOCKOUT_DEFAULT_MAX_ATTEMPTS
+	}
+	cfg.Lockout.MaxAttempts = maxAttempts
+	ipMaxAttempts, err := strconv.ParseInt(os.Getenv("LOCKOUT_IP_MAX_ATTEMPTS"), 10, 64)
+	if err != nil {
+		ipMaxAttempts = LOCKOUT_DEFAULT_IP_MAX_ATTEMPTS
+	}
+	cfg.Lockout.IPMaxAttempts = ipMaxAttempts
+	baseDelay, err := strconv.ParseInt(os.Getenv("LOCKOUT_BASE_DELAY"), 10, 64)
+	if err != nil {
+		baseDelay = LOCKOUT_DEFAULT_BASE_DELAY
+	}
+	cfg.Lockout.BaseDelay = baseDelay
+	maxDelay, err := strconv.ParseInt(os.Getenv("LOCKOUT_MAX_DELAY"), 10, 64)
+	if err != nil {
+		maxDelay = LOCKOUT_DEFAULT_MAX_DELAY
+	}
+	cfg.Lockout.MaxDelay = maxDelay
+	duration, err := strconv.ParseInt(os.Getenv("LOCKOUT_DURATION"), 10, 64)
+	if err != nil {
+		duration = LOCKOUT_DEFAULT_DURATION
+	}
+	cfg.Lockout.Duration = duration
+	window, err := strconv.ParseInt(os.Getenv("LOCKOUT_WINDOW"), 10, 64)
+	if err != nil {
+		window = LOCKOUT_DEFAULT_WINDOW
+	}
+	cfg.Lockout.Window = window
+}
+
 func validation(cfg *Config) error {
 	if cfg.App.Version == "" {
 		return fmt.Errorf("version is required")
@@ -192,16 +502,37 @@ func validation(cfg *Config) error {
 		return fmt.Errorf("mongo database is required")
 	}
 
-	if cfg.AzBlob.Host == "" {
-		return fmt.Errorf("azblob host is required")
-	}
+	switch cfg.Storage.Backend {
+	case STORAGE_BACKEND_AZBLOB:
+		if cfg.AzBlob.Host == "" {
+			return fmt.Errorf("azblob host is required")
+		}
 
-	if cfg.AzBlob.AccountName == "" {
-		return fmt.Errorf("azblob account name is required")
-	}
+		if cfg.AzBlob.AccountName == "" {
+			return fmt.Errorf("azblob account name is required")
+		}
+
+		if cfg.AzBlob.AccountKey == "" {
+			return fmt.Errorf("azblob account key is required")
+		}
+	case STORAGE_BACKEND_S3:
+		if cfg.S3.Endpoint == "" {
+			return fmt.Errorf("s3 endpoint is required")
+		}
+
+		if cfg.S3.AccessKey == "" {
+			return fmt.Errorf("s3 access key is required")
+		}
 
-	if cfg.AzBlob.AccountKey == "" {
-		return fmt.Errorf("azblob account key is required")
+		if cfg.S3.SecretKey == "" {
+			return fmt.Errorf("s3 secret key is required")
+		}
+	case STORAGE_BACKEND_LOCAL:
+		if cfg.Storage.LocalPath == "" {
+			return fmt.Errorf("storage local path is required")
+		}
+	default:
+		return fmt.Errorf("storage backend is invalid")
 	}
 
 	if cfg.Storage.Container == "" {
@@ -236,13 +567,126 @@ func validation(cfg *Config) error {
 		return fmt.Errorf("secret key is required")
 	}
 
+	if cfg.Secret.TokenKey == "" {
+		return fmt.Errorf("secret token key is required")
+	}
+
+	// access tokens must not share a key with the master key and local blob signatures
+	if cfg.Secret.TokenKey == cfg.Secret.SecretKey || cryp.HashMD5(cfg.Secret.TokenKey) == cfg.Secret.SecretKey {
+		return fmt.Errorf("secret token key must differ from the secret key")
+	}
+
+	if cfg.Secret.AccessTokenTTL <= 0 {
+		return fmt.Errorf("secret access token ttl is invalid")
+	}
+
+	if cfg.Secret.RefreshTokenTTL <= cfg.Secret.AccessTokenTTL {
+		return fmt.Errorf("secret refresh token ttl is invalid")
+	}
+
 	if cfg.Upload.MaxSizeMB <= 0 {
 		return fmt.Errorf("upload max size mb is invalid")
 	}
 
+	if cfg.Upload.PendingTTL <= 0 {
+		return fmt.Errorf("upload pending ttl is invalid")
+	}
+
+	if cfg.Upload.CleanupInterval <= 0 {
+		return fmt.Errorf("upload cleanup interval is invalid")
+	}
+
+	if cfg.Upload.PresignExpire <= 0 {
+		return fmt.Errorf("upload presign expire is invalid")
+	}
+
+	// s3 parts are at least 5MB
+	if cfg.Upload.StreamBlockMB <= 0 || (cfg.Storage.Backend == STORAGE_BACKEND_S3 && cfg.Upload.StreamBlockMB < 5) {
+		return fmt.Errorf("upload stream block mb is invalid")
+	}
+
+	if cfg.Upload.StreamParallel <= 0 {
+		return fmt.Errorf("upload stream parallel is invalid")
+	}
+
+	if cfg.Upload.StreamMax <= 0 {
+		return fmt.Errorf("upload stream max is invalid")
+	}
+
+	for mimeType, maxSizeMB := range cfg.Upload.Policy.TypeMaxSizeMB {
+		if mimeType == "" || maxSizeMB <= 0 {
+			return fmt.Errorf("upload type max size is invalid")
+		}
+	}
+
 	if cfg.Download.Expire <= 0 {
 		return fmt.Errorf("download expire is invalid")
 	}
 
+	if cfg.Trash.RetentionDays < 0 {
+		return fmt.Errorf("trash retention days is invalid")
+	}
+
+	if cfg.Trash.PurgeInterval <= 0 {
+		return fmt.Errorf("trash purge interval is invalid")
+	}
+
+	if cfg.LifeTime.SweepInterval <= 0 {
+		return fmt.Errorf("lifetime sweep interval is invalid")
+	}
+
+	switch cfg.Scanner.Backend {
+	case "":
+		if cfg.Scanner.Enforce {
+			return fmt.Errorf("scanner backend is required when scanning is enforced")
+		}
+	case SCANNER_BACKEND_CLAMD:
+		if cfg.Scanner.Address == "" {
+			return fmt.Errorf("scanner address is required")
+		}
+	default:
+		return fmt.Errorf("scanner backend is invalid")
+	}
+
+	if cfg.Scanner.Timeout <= 0 {
+		return fmt.Errorf("scanner timeout is invalid")
+	}
+
+	switch cfg.Scanner.InfectedAction {
+	case SCANNER_INFECTED_ACTION_REJECT, SCANNER_INFECTED_ACTION_QUARANTINE:
+	default:
+		return fmt.Errorf("scanner infected action is invalid")
+	}
+
+	for _, userId := range cfg.Auth.Tokens {
+		if userId <= 0 {
+			return fmt.Errorf("auth token user id is invalid")
+		}
+	}
+
+	if cfg.Auth.Required && len(cfg.Auth.Tokens) == 0 {
+		return fmt.Errorf("auth tokens are required when auth is required")
+	}
+
+	if cfg.Lockout.MaxAttempts <= 0 {
+		return fmt.Errorf("lockout max attempts is invalid")
+	}
+
+	if cfg.Lockout.IPMaxAttempts <= 0 {
+		return fmt.Errorf("lockout ip max attempts is invalid")
+	}
+
+	if cfg.Lockout.BaseDelay < 0 || cfg.Lockout.MaxDelay < cfg.Lockout.BaseDelay {
+		return fmt.Errorf("lockout delay is invalid")
+	}
+
+	if cfg.Lockout.Duration <= 0 {
+		return fmt.Errorf("lockout duration is invalid")
+	}
+
+	if cfg.Lockout.Window <= 0 {
+		return fmt.Errorf("lockout window is invalid")
+	}
+
 	return nil
 }
@@ -0,0 +1,36 @@
+package constants
+
+const (
+	HEADER_AUTHORIZATION = "Authorization"
+	AUTH_SCHEME_BEARER   = "Bearer"
+)
+
+const (
+	AUTH_SCOPE_UPLOAD_PUBLIC  = "upload:public"
+	AUTH_SCOPE_UPLOAD_PRIVATE = "upload:private"
+	AUTH_SCOPE_DOWNLOAD       = "download"
+	AUTH_SCOPE_ADMIN          = "admin"
+)
+
+var AUTH_SCOPES = []string{
+	AUTH_SCOPE_UPLOAD_PUBLIC,
+	AUTH_SCOPE_UPLOAD_PRIVATE,
+	AUTH_SCOPE_DOWNLOAD,
+	AUTH_SCOPE_ADMIN,
+}
+
+const (
+	API_KEY_PREFIX       = "mdo_"
+	API_KEY_SIZE         = 32 // random bytes
+	API_KEY_DISPLAY_SIZE = 12 // leading characters kept to recognize a key
+)
+
+const (
+	FIELD_API_KEY_UUID       = "uuid"
+	FIELD_API_KEY_NAME       = "name"
+	FIELD_API_KEY_KEY_HASH   = "key_hash"
+	FIELD_API_KEY_IS_REVOKED = "is_revoked"
+	FIELD_API_KEY_PARENT_ID  = "parent_id"
+	FIELD_API_KEY_CREATED_BY = "created_by"
+	FIELD_API_KEY_CREATED_AT = "created_at"
+)
@@ -3,8 +3,12 @@ package constants
 const (
 	// Storage
 	STORAGE_ENDPOINT_UPLOAD                    = "/storage/upload"
+	STORAGE_ENDPOINT_UPLOAD_STREAM             = "/storage/upload/stream"
 	STORAGE_ENDPOINT_UPLOAD_STAGE              = "/storage/upload/stage"
 	STORAGE_ENDPOINT_UPLOAD_COMMIT             = "/storage/upload/commit"
+	STORAGE_ENDPOINT_UPLOAD_STATUS             = "/storage/upload/:file_id/status"
+	STORAGE_ENDPOINT_UPLOAD_PRESIGN            = "/storage/upload/presign"
+	STORAGE_ENDPOINT_UPLOAD_FINALIZE           = "/storage/upload/finalize"
 	STORAGE_ENDPOINT_UPLOAD_WITH_SECRET        = "/storage/secret/upload"
 	STORAGE_ENDPOINT_UPLOAD_STAGE_WITH_SECRET  = "/storage/secret/upload/stage"
 	STORAGE_ENDPOINT_UPLOAD_COMMIT_WITH_SECRET = "/storage/secret/upload/commit"
@@ -12,8 +16,38 @@ const (
 	STORAGE_ENDPOINT_REQUEST_DOWNLOAD          = "/storage/download/request/:file_id"
 	STORAGE_ENDPOINT_CREATE_SECRET             = "/storage/secret"
 	STORAGE_ENDPOINT_RETRIEVE_SECRET           = "/storage/secret/retrieve"
+	STORAGE_ENDPOINT_REFRESH_SECRET            = "/storage/secret/refresh"
+	STORAGE_ENDPOINT_LOGOUT_SECRET             = "/storage/secret/logout"
 	STORAGE_ENDPOINT_RESET_PIN_CODE            = "/storage/secret/pin"
+	STORAGE_ENDPOINT_LIST_SECRET_FILES         = "/storage/secret/files"
+	STORAGE_ENDPOINT_DELETE                    = "/storage/file/:file_id"
+	STORAGE_ENDPOINT_DELETE_WITH_SECRET        = "/storage/secret/file/:file_id"
+	STORAGE_ENDPOINT_LIST_SECRET_TRASH         = "/storage/secret/trash"
+	STORAGE_ENDPOINT_RESTORE_WITH_SECRET       = "/storage/secret/trash/:file_id/restore"
+	STORAGE_ENDPOINT_PURGE_WITH_SECRET         = "/storage/secret/trash/:file_id"
+
+	// Auth
+	AUTH_ENDPOINT_API_KEYS = "/auth/api-keys"
+	AUTH_ENDPOINT_API_KEY  = "/auth/api-keys/:key_id"
+
+	// Tus
+	TUS_ENDPOINT_CREATE = "/tus"
+	TUS_ENDPOINT_UPLOAD = "/tus/:file_id"
+
+	// Admin
+	ADMIN_ENDPOINT_SECRETS         = "/secrets"
+	ADMIN_ENDPOINT_SECRET_PASSWORD = "/secrets/:secret_id/password"
+	ADMIN_ENDPOINT_SECRET_PIN_CODE = "/secrets/:secret_id/pin"
+	ADMIN_ENDPOINT_FILES           = "/files"
+	ADMIN_ENDPOINT_FILE            = "/files/:file_id"
+	ADMIN_ENDPOINT_FILE_DOWNLOAD   = "/files/:file_id/download"
+	ADMIN_ENDPOINT_PENDING_UPLOADS = "/upload/pending"
+	ADMIN_ENDPOINT_STATS           = "/stats"
 
 	// Share
 	SHARE_ENDPOINT_DOWNLOAD = "/download/:file_id"
+
+	// Blob
+	BLOB_ENDPOINT_LOCAL_DOWNLOAD = "/local/*file_name"
+	BLOB_ENDPOINT_LOCAL_UPLOAD   = "/local/*file_name"
 )
@@ -17,3 +17,20 @@ const (
 const (
 	SECRET_TYPE_MEDIA = "media"
 )
+
+const (
+	SECRET_PIN_CODE_PATTERN = `^\d{4}$`
+)
+
+const (
+	FIELD_SESSION_UUID       = "uuid"
+	FIELD_SESSION_SECRET_ID  = "secret_id"
+	FIELD_SESSION_IS_REVOKED = "is_revoked"
+	FIELD_SESSION_CREATED_AT = "created_at"
+)
+
+const (
+	SECRET_TOKEN_ISSUER       = "medioa"
+	SECRET_REFRESH_TOKEN_SIZE = 32 // random bytes
+	SECRET_REFRESH_TOKEN_SEP  = "."
+)
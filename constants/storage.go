@@ -1,17 +1,62 @@
 package constants
 
 const (
-	FIELD_STORAGE_ID                = "id"
-	FIELD_STORAGE_UUID              = "_id"
-	FIELD_STORAGE_DOWNLOAD_URL      = "download_url"
-	FIELD_STORAGE_DOWNLOAD_PASSWORD = "download_password"
-	FIELD_STORAGE_TYPE              = "type"
-	FIELD_STORAGE_TOKEN             = "token"
-	FIELD_STORAGE_LIFE_TIME         = "life_time"
-	FIELD_STORAGE_EXT               = "ext"
-	FIELD_STORAGE_SECRET_ID         = "secret_id"
-	FIELD_STORAGE_CREATED_BY        = "created_by"
-	FIELD_STORAGE_CREATED_AT        = "created_at"
+	FIELD_STORAGE_ID                 = "id"
+	FIELD_STORAGE_UUID               = "_id"
+	FIELD_STORAGE_DOWNLOAD_URL       = "download_url"
+	FIELD_STORAGE_DOWNLOAD_PASSWORD  = "download_password"
+	FIELD_STORAGE_TYPE               = "type"
+	FIELD_STORAGE_TOKEN              = "token"
+	FIELD_STORAGE_LIFE_TIME          = "life_time"
+	FIELD_STORAGE_FILE_NAME          = "file_name"
+	FIELD_STORAGE_FILE_SIZE          = "file_size"
+	FIELD_STORAGE_EXT                = "ext"
+	FIELD_STORAGE_SECRET_ID          = "secret_id"
+	FIELD_STORAGE_CREATED_BY         = "created_by"
+	FIELD_STORAGE_CREATED_AT         = "created_at"
+	FIELD_STORAGE_CREATED_FROM       = "created_from"
+	FIELD_STORAGE_CREATED_TO         = "created_to"
+	FIELD_STORAGE_DELETED_AT         = "deleted_at"
+	FIELD_STORAGE_DELETED_BEFORE     = "deleted_before"
+	FIELD_STORAGE_IS_DELETED         = "is_deleted"
+	FIELD_STORAGE_EXPIRED_AT         = "expired_at"
+	FIELD_STORAGE_EXPIRED_BEFORE     = "expired_before"
+	FIELD_STORAGE_IS_RELEASING       = "is_releasing"
+	FIELD_STORAGE_IS_PENDING         = "is_pending"
+	FIELD_STORAGE_SHA256             = "sha256"
+	FIELD_STORAGE_BLOB_NAME          = "blob_name"
+	FIELD_STORAGE_HAS_SECRET         = "has_secret"
+	FIELD_STORAGE_UPLOAD_OFFSET      = "upload_offset"
+	FIELD_STORAGE_PATCH_LOCKED_UNTIL = "patch_locked_until"
+	FIELD_STORAGE_HASH_STATE         = "hash_state"
+	FIELD_STORAGE_HASHED_SIZE        = "hashed_size"
+	FIELD_STORAGE_HASHED_CHUNKS      = "hashed_chunks"
+	FIELD_STORAGE_CHUNK_WRITES       = "chunk_writes"
+)
+
+const (
+	CHECKSUM_ALGORITHM_MD5    = "md5"
+	CHECKSUM_ALGORITHM_SHA1   = "sha1"
+	CHECKSUM_ALGORITHM_SHA256 = "sha256"
+	CHECKSUM_ALGORITHM_CRC32C = "crc32c"
+	HEADER_CONTENT_MD5        = "Content-MD5"
+)
+
+const (
+	UPLOAD_POLICY_TYPE_NOT_ALLOWED = "upload_type_not_allowed"
+	UPLOAD_POLICY_EXT_NOT_ALLOWED  = "upload_ext_not_allowed"
+	UPLOAD_POLICY_SIZE_EXCEEDED    = "upload_size_exceeded"
+	UPLOAD_POLICY_INFECTED         = "upload_infected"
+	UPLOAD_POLICY_SCAN_FAILED      = "upload_scan_failed"
+)
+
+// DOWNLOAD_PASSWORD_SIZE is in random bytes, the password is hex encoded
+const DOWNLOAD_PASSWORD_SIZE = 16
+
+const (
+	SCAN_STATUS_PENDING  = "pending"
+	SCAN_STATUS_CLEAN    = "clean"
+	SCAN_STATUS_INFECTED = "infected"
 )
 
 var (
@@ -0,0 +1,39 @@
+package constants
+
+import "time"
+
+const (
+	TUS_VERSION            = "1.0.0"
+	TUS_EXTENSION          = "creation,termination,checksum"
+	TUS_CHECKSUM_ALGORITHM = "md5,sha1,sha256,crc32c"
+	TUS_CONTENT_TYPE       = "application/offset+octet-stream"
+)
+
+const (
+	TUS_HEADER_RESUMABLE          = "Tus-Resumable"
+	TUS_HEADER_VERSION            = "Tus-Version"
+	TUS_HEADER_EXTENSION          = "Tus-Extension"
+	TUS_HEADER_CHECKSUM_ALGORITHM = "Tus-Checksum-Algorithm"
+	TUS_HEADER_MAX_SIZE           = "Tus-Max-Size"
+	TUS_HEADER_UPLOAD_OFFSET      = "Upload-Offset"
+	TUS_HEADER_UPLOAD_LENGTH      = "Upload-Length"
+	TUS_HEADER_UPLOAD_METADATA    = "Upload-Metadata"
+	TUS_HEADER_UPLOAD_CHECKSUM    = "Upload-Checksum"
+	TUS_HEADER_UPLOAD_SECRET      = "Upload-Secret" // secret of a private upload on follow-up requests
+)
+
+const (
+	TUS_METADATA_FILE_NAME = "filename"
+	TUS_METADATA_FILE_TYPE = "filetype"
+	TUS_METADATA_SECRET    = "secret"
+	TUS_METADATA_LIFE_TIME = "life_time"
+	TUS_METADATA_EXPIRE_AT = "expire_at"
+)
+
+const (
+	TUS_STATUS_CHECKSUM_MISMATCH = 460
+)
+
+// TUS_PATCH_LOCK_TTL outlives staging and committing a block, a patch that died
+// releases its upload when it expires
+const TUS_PATCH_LOCK_TTL = 15 * time.Minute
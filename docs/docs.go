@@ -19,14 +19,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/share/download/{file_id}": {
+        "/admin/files": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Download media file (images, videos, etc.)",
+                "description": "List public and private files of every secret (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,43 +34,102 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Admin"
                 ],
-                "summary": "Download media (public/private)",
+                "summary": "List files",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "file id",
-                        "name": "file_id",
-                        "in": "path",
+                        "description": "master secret",
+                        "name": "secret",
+                        "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size",
+                        "name": "size",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "token",
-                        "name": "token",
-                        "in": "query",
-                        "required": true
+                        "description": "sort by (file_name, file_size, type, ext, created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "order by (asc, desc)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret id of private files",
+                        "name": "secret_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mime type or family (e.g. image/png, image)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file extension (e.g. .png)",
+                        "name": "ext",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name contains",
+                        "name": "file_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created to (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list files in trash",
+                        "name": "deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.DownloadResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.ListFileResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/download/request/{file_id}": {
-            "get": {
+        "/admin/files/{file_id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get download url for private media with download password",
+                "description": "Move any file to trash, it can be restored until purged (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,9 +137,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Request download private media",
+                "summary": "Delete any media",
                 "parameters": [
                     {
                         "type": "string",
@@ -91,15 +150,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "token",
-                        "name": "token",
+                        "description": "master secret",
+                        "name": "secret",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "secret",
-                        "name": "secret",
+                        "description": "master pin code",
+                        "name": "pin_code",
                         "in": "query",
                         "required": true
                     }
@@ -108,20 +167,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.RequestDownloadResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.DeleteResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/download/{file_id}": {
+        "/admin/files/{file_id}/download": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Download media file (images, videos, etc.)",
+                "description": "Get a download url of any file without its secret or download password (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -129,9 +188,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Download media (public/private)",
+                "summary": "Download any media",
                 "parameters": [
                     {
                         "type": "string",
@@ -142,28 +201,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "token",
-                        "name": "token",
+                        "description": "master secret",
+                        "name": "secret",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "secret",
-                        "name": "secret",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "password",
-                        "name": "password",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "silent response",
-                        "name": "silent",
-                        "in": "query"
+                        "description": "master pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -176,14 +224,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage/secret": {
-            "post": {
+        "/admin/secrets": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create new secret for upload media",
+                "description": "List every secret, password and pin code are never returned (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -191,38 +239,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Create new secret",
+                "summary": "List secrets",
                 "parameters": [
                     {
-                        "description": "create secret request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CreateSecretRequest"
-                        }
+                        "type": "string",
+                        "description": "master secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort by (username, type, is_master, created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "order by (asc, desc)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CreateSecretResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.ListSecretResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/secret/pin": {
+        "/admin/secrets/{secret_id}/password": {
             "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Reset pin code for secret",
+                "description": "Reset password of any secret but the master one, its sessions are signed out (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,17 +306,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Reset pin code",
+                "summary": "Reset secret password",
                 "parameters": [
                     {
-                        "description": "reset pin request",
+                        "type": "string",
+                        "description": "secret id",
+                        "name": "secret_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "master secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "master pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "reset password request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.ResetPinCodeRequest"
+                            "$ref": "#/definitions/medioa_internal_storage_models.ResetSecretPasswordRequest"
                         }
                     }
                 ],
@@ -251,14 +348,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage/secret/retrieve": {
+        "/admin/secrets/{secret_id}/pin": {
             "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve secret with new access token",
+                "description": "Reset pin code of any secret but the master one (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -266,94 +363,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Retrieve secret",
+                "summary": "Reset secret pin code",
                 "parameters": [
                     {
-                        "description": "retrieve secrect request",
+                        "type": "string",
+                        "description": "secret id",
+                        "name": "secret_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "master secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "master pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "reset pin request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.RetrieveSecretRequest"
+                            "$ref": "#/definitions/medioa_internal_storage_models.ResetSecretPinCodeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.RetrieveSecretResponse"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/storage/secret/upload": {
-            "post": {
+        "/admin/stats": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Upload media file (images, videos, etc.)",
+                "description": "Get secrets, stored files and blobs, saved size is the storage saved by deduplication (master secret only)",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Upload media with secret",
+                "summary": "Get storage stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "session id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "secret",
+                        "description": "master secret",
                         "name": "secret",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "binary file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "file name",
-                        "name": "file_name",
-                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.StorageStatsResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/secret/upload/commit": {
-            "post": {
+        "/admin/upload/pending": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Commit all chunks to complete upload media file",
+                "description": "Count chunked uploads not committed yet, abandoned ones are older than the pending ttl (master secret only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -361,181 +457,227 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Admin"
                 ],
-                "summary": "Commit upload media chunk with secret",
+                "summary": "Count pending uploads",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "session id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "secret",
+                        "description": "master secret",
                         "name": "secret",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "commit chunk request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.PendingUploadResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/secret/upload/stage": {
-            "post": {
+        "/auth/api-keys": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Upload media file (images, videos, etc.)",
+                "description": "List api keys of the caller, a key without admin scope only sees itself and the keys created with it",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Auth"
                 ],
-                "summary": "Upload media by chunk with secret",
+                "summary": "List api keys",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "session id",
-                        "name": "id",
+                        "type": "boolean",
+                        "description": "include revoked keys",
+                        "name": "include_revoked",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "secret",
-                        "name": "secret",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "binary chunk",
-                        "name": "chunk",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "chunk index",
-                        "name": "chunk_index",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "total chunk",
-                        "name": "total_chunks",
-                        "in": "formData",
-                        "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_auth_models.ListApiKeyResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "file id",
-                        "name": "file_id",
-                        "in": "formData"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a long-lived api key for server-to-server calls, scopes: upload:public, upload:private, download, admin. Requires a static token or an admin key, a key never outlives the key that created it. The key is only returned once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Create api key",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "file name",
-                        "name": "file_name",
-                        "in": "formData"
+                        "description": "create api key request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_auth_models.CreateApiKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.UploadChunkResponse"
+                            "$ref": "#/definitions/medioa_internal_auth_models.CreateApiKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/upload": {
-            "post": {
+        "/auth/api-keys/{key_id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Upload media file (images, videos, etc.), must provide file or url",
+                "description": "Revoke an api key of the caller, it stops authenticating immediately with every key created with it. A key without admin scope only revokes itself and the keys created with it",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Auth"
                 ],
-                "summary": "Upload media",
+                "summary": "Revoke api key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "session id",
-                        "name": "id",
-                        "in": "query"
-                    },
+                        "description": "key id",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_auth_models.RevokeApiKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/blob/local/{file_name}": {
+            "get": {
+                "description": "Serve a blob from the local storage backend with a signed url",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Blob"
+                ],
+                "summary": "Download local blob",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "file url",
-                        "name": "url",
-                        "in": "formData"
+                        "description": "blob name",
+                        "name": "file_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "binary file",
-                        "name": "file",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "expiry (unix seconds)",
+                        "name": "se",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "file name",
+                        "description": "signature",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "put": {
+                "description": "Write a blob to the local storage backend with a signed upload url, an existing blob is never overwritten and the size is limited to the max upload size",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Blob"
+                ],
+                "summary": "Upload local blob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "blob name",
                         "name": "file_name",
-                        "in": "formData"
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "expiry (unix seconds)",
+                        "name": "se",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
-                        }
+                        "description": "Created"
                     }
                 }
             }
         },
-        "/storage/upload/commit": {
-            "post": {
+        "/share/download/{file_id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Commit all chunks to complete upload media file",
+                "description": "Download media file (images, videos, etc.)",
                 "consumes": [
                     "application/json"
                 ],
@@ -543,46 +685,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Share"
                 ],
-                "summary": "Commit upload media chunk",
+                "summary": "Download media (public/private)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "session id",
-                        "name": "id",
-                        "in": "query"
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "commit chunk request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkRequest"
-                        }
+                        "type": "string",
+                        "description": "token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.DownloadResponse"
                         }
                     }
                 }
             }
         },
-        "/storage/upload/stage": {
-            "post": {
+        "/storage/download/request/{file_id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Upload media file (images, videos, etc.)",
+                "description": "Get download url for private media with download password",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -590,60 +731,1654 @@ const docTemplate = `{
                 "tags": [
                     "Storage"
                 ],
-                "summary": "Upload media by chunk",
+                "summary": "Request download private media",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "session id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "file",
-                        "description": "binary chunk",
-                        "name": "chunk",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "chunk index",
-                        "name": "chunk_index",
-                        "in": "formData",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "total chunk",
-                        "name": "total_chunks",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "file id",
-                        "name": "file_id",
-                        "in": "formData"
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "file name",
-                        "name": "file_name",
-                        "in": "formData"
+                        "description": "pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/medioa_internal_storage_models.UploadChunkResponse"
+                            "$ref": "#/definitions/medioa_internal_storage_models.RequestDownloadResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "/storage/download/{file_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Download media file (images, videos, etc.)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Download media (public/private)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "password",
+                        "name": "password",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "silent response",
+                        "name": "silent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.DownloadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/file/{file_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move public media file to trash, it is purged after the retention",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Delete public media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.DeleteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create new secret for upload media",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Create new secret",
+                "parameters": [
+                    {
+                        "description": "create secret request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CreateSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CreateSecretResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/file/{file_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move private media file to trash with secret, it can be restored until purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Delete private media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.DeleteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/files": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List private files uploaded with secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "List secret files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort by (file_name, file_size, type, ext, created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "order by (asc, desc)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mime type or family (e.g. image/png, image)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file extension (e.g. .png)",
+                        "name": "ext",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name contains",
+                        "name": "file_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created to (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.ListFileResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke the session of a refresh token, or every session of the secret with all",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Logout secret",
+                "parameters": [
+                    {
+                        "description": "logout secret request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.LogoutSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.LogoutSecretResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/pin": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reset pin code for secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Reset pin code",
+                "parameters": [
+                    {
+                        "description": "reset pin request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.ResetPinCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/storage/secret/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Exchange a refresh token for a new access token, the refresh token is rotated and can't be used again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Refresh secret",
+                "parameters": [
+                    {
+                        "description": "refresh secret request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.RefreshSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.RefreshSecretResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/retrieve": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve secret with new access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Retrieve secret",
+                "parameters": [
+                    {
+                        "description": "retrieve secrect request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.RetrieveSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.RetrieveSecretResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List private files in trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "List secret trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort by (file_name, file_size, type, ext, created_at, deleted_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "order by (asc, desc)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mime type or family (e.g. image/png, image)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file extension (e.g. .png)",
+                        "name": "ext",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name contains",
+                        "name": "file_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created to (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.ListFileResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/trash/{file_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Permanently delete private media file and its metadata from trash with secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Purge private media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pin code",
+                        "name": "pin_code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.DeleteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/trash/{file_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore private media file from trash with secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Restore private media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.RestoreResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/upload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload media file (images, videos, etc.), must provide file or url",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media with secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "file url",
+                        "name": "url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "binary file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name",
+                        "name": "file_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lifetime in seconds",
+                        "name": "life_time",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expire at (RFC3339)",
+                        "name": "expire_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum algorithm (md5, sha1, sha256, crc32c), default sha256",
+                        "name": "checksum_algorithm",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/upload/commit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Commit all chunks to complete upload media file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Commit upload media chunk with secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "commit chunk request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/secret/upload/stage": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload media file (images, videos, etc.)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media by chunk with secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "binary chunk",
+                        "name": "chunk",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "chunk index",
+                        "name": "chunk_index",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "total chunk",
+                        "name": "total_chunks",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name",
+                        "name": "file_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lifetime in seconds, used on first chunk",
+                        "name": "life_time",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expire at (RFC3339), used on first chunk",
+                        "name": "expire_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum algorithm (md5, sha1, sha256, crc32c), default sha256",
+                        "name": "checksum_algorithm",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadChunkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload media file (images, videos, etc.), must provide file or url",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file url",
+                        "name": "url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "binary file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name",
+                        "name": "file_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lifetime in seconds",
+                        "name": "life_time",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expire at (RFC3339)",
+                        "name": "expire_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum algorithm (md5, sha1, sha256, crc32c), default sha256",
+                        "name": "checksum_algorithm",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/commit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Commit all chunks to complete upload media file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Commit upload media chunk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "commit chunk request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.CommitChunkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Verify a file uploaded with a presigned url, sniff its type and size and complete the upload, secret is required for private upload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Finalize presigned upload media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query"
+                    },
+                    {
+                        "description": "finalize upload request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.FinalizeUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/presign": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reserve a file and get a short-lived write-only url to upload it directly to storage, secret is required for private upload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Presign upload media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query"
+                    },
+                    {
+                        "description": "presign upload request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.PresignUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.PresignUploadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/stage": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload media file (images, videos, etc.)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media by chunk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "binary chunk",
+                        "name": "chunk",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "chunk index",
+                        "name": "chunk_index",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "total chunk",
+                        "name": "total_chunks",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name",
+                        "name": "file_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lifetime in seconds, used on first chunk",
+                        "name": "life_time",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expire at (RFC3339), used on first chunk",
+                        "name": "expire_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum algorithm (md5, sha1, sha256, crc32c), default sha256",
+                        "name": "checksum_algorithm",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadChunkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/stream": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload media file as the raw request body, it is streamed to storage without buffering, secret is required for private upload",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media by stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file name with extension",
+                        "name": "file_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lifetime in seconds",
+                        "name": "life_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expire at (RFC3339)",
+                        "name": "expire_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted",
+                        "name": "checksum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum algorithm (md5, sha1, sha256, crc32c), default sha256",
+                        "name": "checksum_algorithm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/upload/{file_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get received and missing chunk indices of a chunked upload, secret is required for private upload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload media chunk status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret",
+                        "name": "secret",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/medioa_internal_storage_models.UploadStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tus": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a resumable upload (metadata: filename, filetype, secret, life_time, expire_at)",
+                "tags": [
+                    "Tus"
+                ],
+                "summary": "Tus create upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tus version (1.0.0)",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "upload length in bytes",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload metadata",
+                        "name": "Upload-Metadata",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    }
+                }
+            },
+            "options": {
+                "description": "Report supported tus version and extensions",
+                "tags": [
+                    "Tus"
+                ],
+                "summary": "Tus discovery",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/tus/{file_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Terminate an unfinished resumable upload and remove its staged data",
+                "tags": [
+                    "Tus"
+                ],
+                "summary": "Tus terminate upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload token of the location",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tus version (1.0.0)",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret of a private upload",
+                        "name": "Upload-Secret",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get current offset of a resumable upload",
+                "tags": [
+                    "Tus"
+                ],
+                "summary": "Tus upload offset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload token of the location",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tus version (1.0.0)",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret of a private upload",
+                        "name": "Upload-Secret",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Append bytes to a resumable upload at the given offset, the upload is committed on the last byte",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "Tus"
+                ],
+                "summary": "Tus append upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload token of the location",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tus version (1.0.0)",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret of a private upload",
+                        "name": "Upload-Secret",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "upload offset",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "checksum (md5, sha1, sha256, crc32c)",
+                        "name": "Upload-Checksum",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "medioa_internal_auth_models.ApiKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "key that created it, revoking it revokes this one",
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "medioa_internal_auth_models.CreateApiKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expire_at": {
+                    "description": "zero never expires, capped at the expiry of the calling key",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "medioa_internal_auth_models.CreateApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "expired_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "only returned once",
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "medioa_internal_auth_models.ListApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/medioa_internal_auth_models.ApiKey"
+                    }
+                }
+            }
+        },
+        "medioa_internal_auth_models.RevokeApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key_id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                }
+            }
+        },
         "medioa_internal_storage_models.CommitChunkRequest": {
             "type": "object",
             "properties": {
@@ -667,6 +2402,12 @@ const docTemplate = `{
                 "file_size": {
                     "type": "integer"
                 },
+                "scan_status": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
                 },
@@ -698,11 +2439,30 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
+        "medioa_internal_storage_models.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "file_id": {
+                    "type": "string"
+                }
+            }
+        },
         "medioa_internal_storage_models.DownloadResponse": {
             "type": "object",
             "properties": {
@@ -711,6 +2471,199 @@ const docTemplate = `{
                 }
             }
         },
+        "medioa_internal_storage_models.FileResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "ext": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "scan_status": {
+                    "type": "string"
+                },
+                "secret_id": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.FinalizeUploadRequest": {
+            "type": "object",
+            "properties": {
+                "file_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.ListFileResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/medioa_internal_storage_models.FileResponse"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "medioa_internal_storage_models.ListSecretResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/medioa_internal_storage_models.SecretResponse"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "medioa_internal_storage_models.LogoutSecretRequest": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "description": "revoke every session of the secret",
+                    "type": "boolean"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.LogoutSecretResponse": {
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "medioa_internal_storage_models.PendingUploadResponse": {
+            "type": "object",
+            "properties": {
+                "abandoned": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "pending_ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "medioa_internal_storage_models.PresignUploadRequest": {
+            "type": "object",
+            "properties": {
+                "expire_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "life_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "medioa_internal_storage_models.PresignUploadResponse": {
+            "type": "object",
+            "properties": {
+                "expired_at": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "upload_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.RefreshSecretRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.RefreshSecretResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "medioa_internal_storage_models.RequestDownloadResponse": {
             "type": "object",
             "properties": {
@@ -733,6 +2686,54 @@ const docTemplate = `{
                 },
                 "new_pin_code": {
                     "type": "string"
+                },
+                "old_pin_code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.ResetSecretPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "masterPinCode": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "secretId": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.ResetSecretPinCodeRequest": {
+            "type": "object",
+            "properties": {
+                "masterPinCode": {
+                    "type": "string"
+                },
+                "pin_code": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "secretId": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.RestoreResponse": {
+            "type": "object",
+            "properties": {
+                "file_id": {
+                    "type": "string"
                 }
             }
         },
@@ -753,8 +2754,62 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.SecretResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "is_master": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "medioa_internal_storage_models.StorageStatsResponse": {
+            "type": "object",
+            "properties": {
+                "blobs": {
+                    "type": "integer"
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "logical_size": {
+                    "type": "integer"
+                },
+                "physical_size": {
+                    "type": "integer"
+                },
+                "saved_size": {
+                    "type": "integer"
+                },
+                "secrets": {
+                    "type": "integer"
                 }
             }
         },
@@ -772,6 +2827,9 @@ const docTemplate = `{
         "medioa_internal_storage_models.UploadResponse": {
             "type": "object",
             "properties": {
+                "expired_at": {
+                    "type": "string"
+                },
                 "ext": {
                     "type": "string"
                 },
@@ -784,6 +2842,12 @@ const docTemplate = `{
                 "file_size": {
                     "type": "integer"
                 },
+                "scan_status": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
                 },
@@ -791,6 +2855,35 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "medioa_internal_storage_models.UploadStatusResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "received": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "received_size": {
+                    "type": "integer"
+                },
+                "total_chunks": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"medioa/config"
+	"medioa/constants"
+
+	initAuth "medioa/internal/auth/init"
+	authMiddleware "medioa/internal/auth/middleware"
+	authModel "medioa/internal/auth/models"
+	initStorage "medioa/internal/storage/init"
+	storageModel "medioa/internal/storage/models"
+	storageUC "medioa/internal/storage/usecase"
+	commonModel "medioa/models"
+	"medioa/pkg/xhttp"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	cfg       *config.Config
+	lib       *commonModel.Lib
+	storageUC storageUC.IUsecase
+	auth      authMiddleware.IMiddleware
+}
+
+func InitHandler(cfg *config.Config, lib *commonModel.Lib, storage *initStorage.Init, auth *initAuth.Init) IHandler {
+	return Handler{
+		cfg:       cfg,
+		lib:       lib,
+		storageUC: storage.Usecase,
+		auth:      auth.Middleware,
+	}
+}
+
+// MapRoutes maps the admin routes, every one of them also verifies the master secret
+func (h Handler) MapRoutes(group *gin.RouterGroup) {
+	group.Use(h.auth.Authenticate(constants.AUTH_SCOPE_ADMIN))
+
+	group.GET(constants.ADMIN_ENDPOINT_SECRETS, h.ListSecrets)
+	group.PUT(constants.ADMIN_ENDPOINT_SECRET_PASSWORD, h.ResetSecretPassword)
+	group.PUT(constants.ADMIN_ENDPOINT_SECRET_PIN_CODE, h.ResetSecretPinCode)
+	group.GET(constants.ADMIN_ENDPOINT_FILES, h.ListFiles)
+	group.GET(constants.ADMIN_ENDPOINT_FILE_DOWNLOAD, h.Download)
+	group.DELETE(constants.ADMIN_ENDPOINT_FILE, h.Delete)
+	group.GET(constants.ADMIN_ENDPOINT_PENDING_UPLOADS, h.CountPendingUploads)
+	group.GET(constants.ADMIN_ENDPOINT_STATS, h.GetStorageStats)
+}
+
+// ListSecrets godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		List secrets
+//	@Description	List every secret, password and pin code are never returned (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret		query		string	true	"master secret"
+//	@Param			page		query		int64	false	"page"
+//	@Param			size		query		int64	false	"size"
+//	@Param			sort_by		query		string	false	"sort by (username, type, is_master, created_at)"
+//	@Param			order_by	query		string	false	"order by (asc, desc)"
+//	@Param			username	query		string	false	"username"
+//	@Success		200			{object}	storageModel.ListSecretResponse
+//	@Router			/admin/secrets [get]
+func (h Handler) ListSecrets(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &storageModel.ListSecretsRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.storageUC.ListSecrets(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// ResetSecretPassword godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Reset secret password
+//	@Description	Reset password of any secret but the master one, its sessions are signed out (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret_id	path	string									true	"secret id"
+//	@Param			secret		query	string									true	"master secret"
+//	@Param			pin_code	query	string									true	"master pin code"
+//	@Param			body		body	storageModel.ResetSecretPasswordRequest	true	"reset password request"
+//	@Success		200
+//	@Router			/admin/secrets/{secret_id}/password [put]
+func (h Handler) ResetSecretPassword(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &storageModel.ResetSecretPasswordRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	req.SecretId = ctx.Param("secret_id")
+	req.Secret = ctx.Query("secret")
+	req.MasterPinCode = ctx.Query("pin_code")
+	res, err := h.storageUC.ResetSecretPassword(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// ResetSecretPinCode godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Reset secret pin code
+//	@Description	Reset pin code of any secret but the master one (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret_id	path	string									true	"secret id"
+//	@Param			secret		query	string									true	"master secret"
+//	@Param			pin_code	query	string									true	"master pin code"
+//	@Param			body		body	storageModel.ResetSecretPinCodeRequest	true	"reset pin request"
+//	@Success		200
+//	@Router			/admin/secrets/{secret_id}/pin [put]
+func (h Handler) ResetSecretPinCode(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &storageModel.ResetSecretPinCodeRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	req.SecretId = ctx.Param("secret_id")
+	req.Secret = ctx.Query("secret")
+	req.MasterPinCode = ctx.Query("pin_code")
+	res, err := h.storageUC.ResetSecretPinCode(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// ListFiles godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		List files
+//	@Description	List public and private files of every secret (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret		query		string	true	"master secret"
+//	@Param			page		query		int64	false	"page"
+//	@Param			size		query		int64	false	"size"
+//	@Param			sort_by		query		string	false	"sort by (file_name, file_size, type, ext, created_at)"
+//	@Param			order_by	query		string	false	"order by (asc, desc)"
+//	@Param			secret_id	query		string	false	"secret id of private files"
+//	@Param			type		query		string	false	"mime type or family (e.g. image/png, image)"
+//	@Param			ext			query		string	false	"file extension (e.g. .png)"
+//	@Param			file_name	query		string	false	"file name contains"
+//	@Param			from		query		string	false	"created from (RFC3339)"
+//	@Param			to			query		string	false	"created to (RFC3339)"
+//	@Param			deleted		query		bool	false	"list files in trash"
+//	@Success		200			{object}	storageModel.ListFileResponse
+//	@Router			/admin/files [get]
+func (h Handler) ListFiles(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &storageModel.ListFilesRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.storageUC.ListFiles(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// Download godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Download any media
+//	@Description	Get a download url of any file without its secret or download password (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id		path		string	true	"file id"
+//	@Param			secret		query		string	true	"master secret"
+//	@Param			pin_code	query		string	true	"master pin code"
+//	@Success		200			{object}	storageModel.DownloadResponse
+//	@Router			/admin/files/{file_id}/download [get]
+func (h Handler) Download(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	pinCode := ctx.Query("pin_code")
+	res, err := h.storageUC.AdminDownload(ctx, userId, &storageModel.AdminFileRequest{
+		FileId:  fileId,
+		Secret:  secret,
+		PinCode: pinCode,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// Delete godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Delete any media
+//	@Description	Move any file to trash, it can be restored until purged (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id		path		string	true	"file id"
+//	@Param			secret		query		string	true	"master secret"
+//	@Param			pin_code	query		string	true	"master pin code"
+//	@Success		200			{object}	storageModel.DeleteResponse
+//	@Router			/admin/files/{file_id} [delete]
+func (h Handler) Delete(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	pinCode := ctx.Query("pin_code")
+	res, err := h.storageUC.AdminDelete(ctx, userId, &storageModel.AdminFileRequest{
+		FileId:  fileId,
+		Secret:  secret,
+		PinCode: pinCode,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// CountPendingUploads godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Count pending uploads
+//	@Description	Count chunked uploads not committed yet, abandoned ones are older than the pending ttl (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret	query		string	true	"master secret"
+//	@Success		200		{object}	storageModel.PendingUploadResponse
+//	@Router			/admin/upload/pending [get]
+func (h Handler) CountPendingUploads(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	secret := ctx.Query("secret")
+	res, err := h.storageUC.CountPendingUploads(ctx, userId, &storageModel.PendingUploadRequest{
+		Secret: secret,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// GetStorageStats godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Get storage stats
+//	@Description	Get secrets, stored files and blobs, saved size is the storage saved by deduplication (master secret only)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret	query		string	true	"master secret"
+//	@Success		200		{object}	storageModel.StorageStatsResponse
+//	@Router			/admin/stats [get]
+func (h Handler) GetStorageStats(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	secret := ctx.Query("secret")
+	res, err := h.storageUC.GetStorageStats(ctx, userId, &storageModel.StorageStatsRequest{
+		Secret: secret,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
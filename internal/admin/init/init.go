@@ -0,0 +1,25 @@
+package init
+
+import (
+	"medioa/config"
+	"medioa/internal/admin/handler"
+	initAuth "medioa/internal/auth/init"
+	initStorage "medioa/internal/storage/init"
+	commonModel "medioa/models"
+)
+
+type Init struct {
+	Handler handler.IHandler
+}
+
+func NewInit(
+	cfg *config.Config,
+	lib *commonModel.Lib,
+	initStorage *initStorage.Init,
+	initAuth *initAuth.Init,
+) *Init {
+	handler := handler.InitHandler(cfg, lib, initStorage, initAuth)
+	return &Init{
+		Handler: handler,
+	}
+}
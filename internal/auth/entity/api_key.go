@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"medioa/internal/auth/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type ApiKey struct {
+	UUID      string     `bson:"_id"`
+	Name      string     `bson:"name"`
+	Prefix    string     `bson:"prefix"`
+	KeyHash   string     `bson:"key_hash"`
+	Scopes    []string   `bson:"scopes"`
+	ParentId  string     `bson:"parent_id"`
+	ExpiredAt time.Time  `bson:"expired_at"`
+	RevokedAt *time.Time `bson:"revoked_at"`
+	CreatedBy int64      `bson:"created_by"`
+	CreatedAt time.Time  `bson:"created_at"`
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (e *ApiKey) Export() *models.ApiKeyResponse {
+	return &models.ApiKeyResponse{
+		UUID:      e.UUID,
+		Name:      e.Name,
+		Prefix:    e.Prefix,
+		Scopes:    e.Scopes,
+		ParentId:  e.ParentId,
+		ExpiredAt: e.ExpiredAt,
+		RevokedAt: e.RevokedAt,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func (e *ApiKey) ExportList(objs []*ApiKey) []*models.ApiKeyResponse {
+	res := make([]*models.ApiKeyResponse, 0)
+	for _, obj := range objs {
+		res = append(res, obj.Export())
+	}
+	return res
+}
+
+func (e *ApiKey) ParseFromSaveRequest(req *models.ApiKeySaveRequest) {
+	if req != nil {
+		e.UUID = req.UUID
+		e.Name = req.Name
+		e.Prefix = req.Prefix
+		e.KeyHash = req.KeyHash
+		e.Scopes = req.Scopes
+		e.ParentId = req.ParentId
+		e.ExpiredAt = req.ExpiredAt
+		e.RevokedAt = req.RevokedAt
+		e.CreatedBy = req.CreatedBy
+		e.CreatedAt = req.CreatedAt
+	}
+}
+
+func (e *ApiKey) ParseForCreate(req *models.ApiKeySaveRequest, userId int64) {
+	e.ParseFromSaveRequest(req)
+	e.CreatedBy = userId
+	e.CreatedAt = time.Now()
+}
+
+func (e *ApiKey) ParseForUpdate(req *models.ApiKeySaveRequest, userId int64) {
+	e.ParseFromSaveRequest(req)
+}
+
+func (e *ApiKey) ToBson() bson.D {
+	d := make(bson.D, 0)
+	if e.UUID != "" {
+		d = append(d, bson.E{Key: "_id", Value: e.UUID})
+	}
+	if e.Name != "" {
+		d = append(d, bson.E{Key: "name", Value: e.Name})
+	}
+	if e.Prefix != "" {
+		d = append(d, bson.E{Key: "prefix", Value: e.Prefix})
+	}
+	if e.KeyHash != "" {
+		d = append(d, bson.E{Key: "key_hash", Value: e.KeyHash})
+	}
+	if e.Scopes != nil {
+		d = append(d, bson.E{Key: "scopes", Value: e.Scopes})
+	}
+	if e.ParentId != "" {
+		d = append(d, bson.E{Key: "parent_id", Value: e.ParentId})
+	}
+	if !e.ExpiredAt.IsZero() {
+		d = append(d, bson.E{Key: "expired_at", Value: e.ExpiredAt.UnixMilli()})
+	}
+	if e.RevokedAt != nil && !e.RevokedAt.IsZero() {
+		d = append(d, bson.E{Key: "revoked_at", Value: e.RevokedAt.UnixMilli()})
+	}
+	if e.CreatedBy > 0 {
+		d = append(d, bson.E{Key: "created_by", Value: e.CreatedBy})
+	}
+	if !e.CreatedAt.IsZero() {
+		d = append(d, bson.E{Key: "created_at", Value: e.CreatedAt.UnixMilli()})
+	}
+	return d
+}
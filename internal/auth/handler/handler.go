@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/auth/middleware"
+	"medioa/internal/auth/models"
+	"medioa/internal/auth/usecase"
+	commonModel "medioa/models"
+	"medioa/pkg/xhttp"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	cfg        *config.Config
+	lib        *commonModel.Lib
+	usecase    usecase.IUsecase
+	middleware middleware.IMiddleware
+}
+
+func InitHandler(cfg *config.Config, lib *commonModel.Lib, usecase usecase.IUsecase, middleware middleware.IMiddleware) IHandler {
+	return Handler{
+		cfg:        cfg,
+		lib:        lib,
+		usecase:    usecase,
+		middleware: middleware,
+	}
+}
+
+func (h Handler) MapRoutes(group *gin.RouterGroup) {
+	authenticate := h.middleware.Authenticate()
+
+	group.POST(constants.AUTH_ENDPOINT_API_KEYS, authenticate, h.CreateApiKey)
+	group.GET(constants.AUTH_ENDPOINT_API_KEYS, authenticate, h.ListApiKeys)
+	group.DELETE(constants.AUTH_ENDPOINT_API_KEY, authenticate, h.RevokeApiKey)
+}
+
+// CreateApiKey godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Create api key
+//	@Description	Create a long-lived api key for server-to-server calls, scopes: upload:public, upload:private, download, admin. Requires a static token or an admin key, a key never outlives the key that created it. The key is only returned once
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.CreateApiKeyRequest	true	"create api key request"
+//	@Success		201		{object}	models.CreateApiKeyResponse
+//	@Router			/auth/api-keys [post]
+func (h Handler) CreateApiKey(ctx *gin.Context) {
+	userId := models.GetPrincipal(ctx).UserId
+	req := &models.CreateApiKeyRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.CreateApiKey(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Created(ctx, res)
+}
+
+// ListApiKeys godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		List api keys
+//	@Description	List api keys of the caller, a key without admin scope only sees itself and the keys created with it
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			include_revoked	query		bool	false	"include revoked keys"
+//	@Success		200				{object}	models.ListApiKeyResponse
+//	@Router			/auth/api-keys [get]
+func (h Handler) ListApiKeys(ctx *gin.Context) {
+	userId := models.GetPrincipal(ctx).UserId
+	req := &models.ListApiKeyRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.ListApiKeys(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// RevokeApiKey godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Revoke api key
+//	@Description	Revoke an api key of the caller, it stops authenticating immediately with every key created with it. A key without admin scope only revokes itself and the keys created with it
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			key_id	path		string	true	"key id"
+//	@Success		200		{object}	models.RevokeApiKeyResponse
+//	@Router			/auth/api-keys/{key_id} [delete]
+func (h Handler) RevokeApiKey(ctx *gin.Context) {
+	userId := models.GetPrincipal(ctx).UserId
+	res, err := h.usecase.RevokeApiKey(ctx, userId, &models.RevokeApiKeyRequest{
+		KeyId: ctx.Param("key_id"),
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
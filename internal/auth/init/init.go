@@ -0,0 +1,39 @@
+package init
+
+import (
+	"medioa/config"
+	"medioa/internal/auth/handler"
+	"medioa/internal/auth/middleware"
+	"medioa/internal/auth/provider"
+	"medioa/internal/auth/repository"
+	"medioa/internal/auth/service"
+	"medioa/internal/auth/usecase"
+	commonModel "medioa/models"
+)
+
+type Init struct {
+	Repository repository.IRepository
+	Service    service.IService
+	Usecase    usecase.IUsecase
+	Middleware middleware.IMiddleware
+	Handler    handler.IHandler
+}
+
+func NewInit(
+	cfg *config.Config,
+	lib *commonModel.Lib,
+) *Init {
+	repository := repository.InitMongo(cfg, lib)
+	service := service.InitService(cfg, lib, repository)
+	usecase := usecase.InitUsecase(cfg, service)
+	// static tokens first, they may look like api keys
+	middleware := middleware.InitMiddleware(cfg, provider.InitToken(cfg), provider.InitApiKey(service))
+	handler := handler.InitHandler(cfg, lib, usecase, middleware)
+	return &Init{
+		Repository: repository,
+		Service:    service,
+		Usecase:    usecase,
+		Middleware: middleware,
+		Handler:    handler,
+	}
+}
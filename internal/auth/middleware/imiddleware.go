@@ -0,0 +1,11 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type IMiddleware interface {
+	Identify() gin.HandlerFunc
+	Authenticate(scopes ...string) gin.HandlerFunc
+	Authorize(scopes ...string) gin.HandlerFunc
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/auth/models"
+	"medioa/internal/auth/provider"
+	"medioa/pkg/xhttp"
+	"strings"
+
+	"github.com/vukyn/kuery/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type middleware struct {
+	cfg       *config.Config
+	providers []provider.IProvider
+}
+
+func InitMiddleware(cfg *config.Config, providers ...provider.IProvider) IMiddleware {
+	return &middleware{
+		cfg:       cfg,
+		providers: providers,
+	}
+}
+
+// Identify resolves the caller from the Authorization header, a missing credential
+// is an anonymous caller, a wrong one is rejected
+func (m *middleware) Identify() gin.HandlerFunc {
+	log := log.New("middleware", "Identify")
+
+	return func(ctx *gin.Context) {
+		credential := parseAuthorization(ctx.GetHeader(constants.HEADER_AUTHORIZATION))
+		if credential == "" {
+			principal := models.Anonymous()
+			principal.ClientIP = ctx.ClientIP()
+			models.SetPrincipal(ctx, principal)
+			ctx.Next()
+			return
+		}
+
+		for _, p := range m.providers {
+			principal, err := p.Authenticate(ctx, credential)
+			if err != nil {
+				if !errors.Is(err, provider.ErrInvalidCredential) {
+					log.Error("provider.Authenticate", err)
+				}
+				xhttp.Unauthorized(ctx, fmt.Errorf("authorization is invalid"))
+				ctx.Abort()
+				return
+			}
+			if principal != nil {
+				principal.ClientIP = ctx.ClientIP()
+				models.SetPrincipal(ctx, principal)
+				ctx.Next()
+				return
+			}
+		}
+
+		xhttp.Unauthorized(ctx, fmt.Errorf("authorization is invalid"))
+		ctx.Abort()
+	}
+}
+
+// Authenticate requires the identified caller to hold the scopes,
+// anonymous callers are let through unless auth is required
+func (m *middleware) Authenticate(scopes ...string) gin.HandlerFunc {
+	return m.authorize(m.cfg.Auth.Required, scopes)
+}
+
+// Authorize is Authenticate for routes also reachable with a share link,
+// anonymous callers are always let through
+func (m *middleware) Authorize(scopes ...string) gin.HandlerFunc {
+	return m.authorize(false, scopes)
+}
+
+func (m *middleware) authorize(required bool, scopes []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal := models.GetPrincipal(ctx)
+		if principal.IsAnonymous() {
+			if required {
+				xhttp.Unauthorized(ctx, fmt.Errorf("authorization is required"))
+				ctx.Abort()
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				xhttp.Forbidden(ctx, fmt.Errorf("scope %s is required", scope))
+				ctx.Abort()
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// parseAuthorization accepts "Bearer <credential>" or the bare credential
+func parseAuthorization(header string) string {
+	header = strings.TrimSpace(header)
+	if scheme, credential, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, constants.AUTH_SCHEME_BEARER) {
+		return strings.TrimSpace(credential)
+	}
+	return header
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"medioa/constants"
+	commonModel "medioa/models"
+	"strings"
+	"time"
+)
+
+type ApiKeyRequestParams struct {
+	commonModel.RequestParams
+	UUID      string
+	Name      string
+	KeyHash   string
+	ParentId  string
+	IsRevoked *bool
+	CreatedBy int64
+}
+
+func (r *ApiKeyRequestParams) trimSpace() {
+	r.UUID = strings.TrimSpace(r.UUID)
+	r.Name = strings.TrimSpace(r.Name)
+	r.KeyHash = strings.TrimSpace(r.KeyHash)
+	r.ParentId = strings.TrimSpace(r.ParentId)
+}
+func (r *ApiKeyRequestParams) ToMap() map[string]any {
+	r.trimSpace()
+
+	if strings.ToLower(r.OrderBy) != constants.SORT_ORDER_ASC {
+		r.OrderBy = constants.SORT_ORDER_DESC
+	}
+	return map[string]any{
+		constants.FIELD_API_KEY_UUID:       r.UUID,
+		constants.FIELD_API_KEY_NAME:       r.Name,
+		constants.FIELD_API_KEY_KEY_HASH:   r.KeyHash,
+		constants.FIELD_API_KEY_PARENT_ID:  r.ParentId,
+		constants.FIELD_API_KEY_IS_REVOKED: r.IsRevoked,
+		constants.FIELD_API_KEY_CREATED_BY: r.CreatedBy,
+		constants.FIELD_ORDER_BY:           r.OrderBy,
+		constants.FIELD_SORT_BY:            r.SortBy,
+		constants.FIELD_SORT_MULTIPLE:      r.SortMultiple,
+	}
+}
+
+type ApiKeyResponse struct {
+	UUID      string
+	Name      string
+	Prefix    string
+	Scopes    []string
+	ParentId  string
+	ExpiredAt time.Time
+	RevokedAt *time.Time
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+// IsActive reports whether the key can still authenticate
+func (r *ApiKeyResponse) IsActive() bool {
+	if r.RevokedAt != nil {
+		return false
+	}
+	return r.ExpiredAt.IsZero() || r.ExpiredAt.After(time.Now())
+}
+
+type ApiKeySaveRequest struct {
+	UUID      string
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	ParentId  string
+	ExpiredAt time.Time
+	RevokedAt *time.Time
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+type CreateApiKeyRequest struct {
+	Name     string    `json:"name"`
+	Scopes   []string  `json:"scopes"`
+	ExpireAt time.Time `json:"expire_at"` // zero never expires, capped at the expiry of the calling key
+}
+
+type CreateApiKeyResponse struct {
+	KeyId     string     `json:"key_id"`
+	Name      string     `json:"name"`
+	Key       string     `json:"key"` // only returned once
+	Scopes    []string   `json:"scopes"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+type ListApiKeyRequest struct {
+	IncludeRevoked bool `form:"include_revoked"`
+}
+
+type ApiKey struct {
+	KeyId     string     `json:"key_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ParentId  string     `json:"parent_id,omitempty"` // key that created it, revoking it revokes this one
+	ExpiredAt *time.Time `json:"expired_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ListApiKeyResponse struct {
+	Keys []*ApiKey `json:"keys"`
+}
+
+type RevokeApiKeyRequest struct {
+	KeyId string
+}
+
+type RevokeApiKeyResponse struct {
+	KeyId     string    `json:"key_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
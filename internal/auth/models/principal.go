@@ -0,0 +1,55 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AUTH_METHOD_ANONYMOUS = "anonymous"
+	AUTH_METHOD_TOKEN     = "token"
+	AUTH_METHOD_API_KEY   = "api_key"
+)
+
+// principalKey is the gin.Context key of the caller, gin.Context is passed down to usecases
+// as context.Context so the principal is available there too
+const principalKey = "auth.principal"
+
+// Principal is the authenticated caller of a request, KeyId and ExpiredAt are set for api keys
+type Principal struct {
+	UserId    int64
+	Method    string
+	Scopes    []string
+	ClientIP  string
+	KeyId     string
+	ExpiredAt time.Time
+}
+
+func (p *Principal) IsAnonymous() bool {
+	return p.Method == AUTH_METHOD_ANONYMOUS
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func Anonymous() *Principal {
+	return &Principal{
+		Method: AUTH_METHOD_ANONYMOUS,
+	}
+}
+
+func SetPrincipal(ctx *gin.Context, principal *Principal) {
+	ctx.Set(principalKey, principal)
+}
+
+// GetPrincipal returns the caller of a request, anonymous when none was resolved
+func GetPrincipal(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(principalKey).(*Principal); ok && principal != nil {
+		return principal
+	}
+	return Anonymous()
+}
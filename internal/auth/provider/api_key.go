@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"medioa/constants"
+	"medioa/internal/auth/models"
+	"medioa/internal/auth/service"
+	"strings"
+)
+
+// apiKey resolves keys created through the api key endpoints, they are looked up by hash
+type apiKey struct {
+	service service.IService
+}
+
+func InitApiKey(service service.IService) IProvider {
+	return &apiKey{
+		service: service,
+	}
+}
+
+func (a *apiKey) Authenticate(ctx context.Context, credential string) (*models.Principal, error) {
+	if !strings.HasPrefix(credential, constants.API_KEY_PREFIX) {
+		return nil, nil
+	}
+
+	key, err := a.service.GetOne(ctx, &models.ApiKeyRequestParams{
+		KeyHash: HashApiKey(credential),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !key.IsActive() {
+		return nil, ErrInvalidCredential
+	}
+
+	return &models.Principal{
+		UserId:    key.CreatedBy,
+		Method:    models.AUTH_METHOD_API_KEY,
+		Scopes:    key.Scopes,
+		KeyId:     key.UUID,
+		ExpiredAt: key.ExpiredAt,
+	}, nil
+}
+
+// HashApiKey returns the stored form of a key, keys are random so a plain sha256 is enough
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
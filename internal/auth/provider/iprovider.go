@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"medioa/internal/auth/models"
+)
+
+// ErrInvalidCredential is returned by a provider that recognizes a credential it can't accept
+var ErrInvalidCredential = errors.New("invalid credential")
+
+// IProvider resolves a credential to a principal, a nil principal means the credential
+// is not handled by this provider and the next one is tried.
+type IProvider interface {
+	Authenticate(ctx context.Context, credential string) (*models.Principal, error)
+}
@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"context"
+	"crypto/subtle"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/auth/models"
+)
+
+// token resolves static tokens from the config (AUTH_TOKENS) to their user id, with every scope
+type token struct {
+	cfg *config.Config
+}
+
+func InitToken(cfg *config.Config) IProvider {
+	return &token{
+		cfg: cfg,
+	}
+}
+
+func (t *token) Authenticate(ctx context.Context, credential string) (*models.Principal, error) {
+	for token, userId := range t.cfg.Auth.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(credential)) == 1 {
+			return &models.Principal{
+				UserId: userId,
+				Method: models.AUTH_METHOD_TOKEN,
+				Scopes: constants.AUTH_SCOPES,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"medioa/internal/auth/entity"
+)
+
+type IRepository interface {
+	GetOne(ctx context.Context, queries map[string]any) (*entity.ApiKey, error)
+	GetList(ctx context.Context, queries map[string]any) ([]*entity.ApiKey, error)
+	Create(ctx context.Context, obj *entity.ApiKey) (*entity.ApiKey, error)
+	Update(ctx context.Context, obj *entity.ApiKey) (*entity.ApiKey, error)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/auth/entity"
+	commonModel "medioa/models"
+	"medioa/pkg/xmongo"
+
+	"github.com/vukyn/kuery/conv"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongo struct {
+	cfg       *config.Config
+	lib       *commonModel.Lib
+	tableName string
+}
+
+func InitMongo(cfg *config.Config, lib *commonModel.Lib) IRepository {
+	return &mongo{
+		cfg:       cfg,
+		lib:       lib,
+		tableName: (&entity.ApiKey{}).TableName(),
+	}
+}
+
+func (m *mongo) withCollection() *mongoo.Collection {
+	return m.lib.Mongo.Database(m.cfg.Mongo.Database).Collection(m.tableName)
+}
+
+func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.ApiKey, error) {
+	filter := m.filter(queries)
+
+	var obj entity.ApiKey
+	err := m.withCollection().FindOne(ctx, filter).Decode(&obj)
+	if err == mongoo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+func (m *mongo) GetList(ctx context.Context, queries map[string]any) ([]*entity.ApiKey, error) {
+	filter := m.filter(queries)
+	opts := options.Find().SetSort(m.sort(queries))
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.ApiKey{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+func (m *mongo) Create(ctx context.Context, obj *entity.ApiKey) (*entity.ApiKey, error) {
+	_, err := m.withCollection().InsertOne(ctx, obj.ToBson())
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+func (m *mongo) Update(ctx context.Context, obj *entity.ApiKey) (*entity.ApiKey, error) {
+	_, err := m.withCollection().UpdateOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}}, bson.D{{Key: "$set", Value: obj.ToBson()}})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (m *mongo) sort(queries map[string]any) bson.D {
+	sortMultiple := conv.ReadInterface(queries, constants.FIELD_SORT_MULTIPLE, "")
+	sortBy := conv.ReadInterface(queries, constants.FIELD_SORT_BY, "")
+	orderBy := conv.ReadInterface(queries, constants.FIELD_ORDER_BY, constants.DEFAULT_SORT_ORDER)
+	allowed := map[string]string{
+		constants.FIELD_API_KEY_UUID:       "_id",
+		constants.FIELD_API_KEY_NAME:       "name",
+		constants.FIELD_API_KEY_CREATED_BY: "created_by",
+		constants.FIELD_API_KEY_CREATED_AT: "created_at",
+	}
+	return xmongo.Sort(sortMultiple, sortBy, orderBy, allowed, bson.D{{Key: "created_at", Value: -1}})
+}
+
+func (m *mongo) filter(queries map[string]any) bson.D {
+	filter := make(bson.D, 0)
+	uuid := conv.ReadInterface(queries, constants.FIELD_API_KEY_UUID, "")
+	name := conv.ReadInterface(queries, constants.FIELD_API_KEY_NAME, "")
+	keyHash := conv.ReadInterface(queries, constants.FIELD_API_KEY_KEY_HASH, "")
+	parentId := conv.ReadInterface(queries, constants.FIELD_API_KEY_PARENT_ID, "")
+	isRevoked := conv.ReadInterface(queries, constants.FIELD_API_KEY_IS_REVOKED, (*bool)(nil))
+	createdBy := conv.ReadInterface(queries, constants.FIELD_API_KEY_CREATED_BY, int64(0))
+
+	if uuid != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: uuid})
+	}
+	if name != "" {
+		filter = append(filter, bson.E{Key: "name", Value: name})
+	}
+	if keyHash != "" {
+		filter = append(filter, bson.E{Key: "key_hash", Value: keyHash})
+	}
+	if parentId != "" {
+		filter = append(filter, bson.E{Key: "parent_id", Value: parentId})
+	}
+	if isRevoked != nil {
+		if *isRevoked {
+			filter = append(filter, bson.E{Key: "revoked_at", Value: bson.D{{Key: "$ne", Value: nil}}})
+		} else {
+			// revoked_at is only written when revoked
+			filter = append(filter, bson.E{Key: "revoked_at", Value: nil})
+		}
+	}
+	if createdBy != 0 {
+		filter = append(filter, bson.E{Key: "created_by", Value: createdBy})
+	}
+	return filter
+}
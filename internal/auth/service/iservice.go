@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"medioa/internal/auth/models"
+)
+
+type IService interface {
+	GetOne(ctx context.Context, params *models.ApiKeyRequestParams) (*models.ApiKeyResponse, error)
+	GetList(ctx context.Context, params *models.ApiKeyRequestParams) ([]*models.ApiKeyResponse, error)
+	Create(ctx context.Context, userId int64, params *models.ApiKeySaveRequest) (*models.ApiKeyResponse, error)
+	Update(ctx context.Context, userId int64, params *models.ApiKeySaveRequest) (*models.ApiKeyResponse, error)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/internal/auth/entity"
+	"medioa/internal/auth/models"
+	repo "medioa/internal/auth/repository"
+	commonModel "medioa/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+type service struct {
+	cfg  *config.Config
+	lib  *commonModel.Lib
+	repo repo.IRepository
+}
+
+func InitService(cfg *config.Config, lib *commonModel.Lib, repo repo.IRepository) IService {
+	return &service{
+		cfg:  cfg,
+		lib:  lib,
+		repo: repo,
+	}
+}
+
+func (s *service) GetOne(ctx context.Context, params *models.ApiKeyRequestParams) (*models.ApiKeyResponse, error) {
+	log := log.New("service", "GetOne")
+	queries := params.ToMap()
+	record, err := s.repo.GetOne(ctx, queries)
+	if err != nil {
+		log.Error("service.repo.GetOne: %v", err)
+		return nil, err
+	}
+	if record == nil {
+		return nil, nil
+	}
+	return record.Export(), nil
+}
+
+func (s *service) GetList(ctx context.Context, params *models.ApiKeyRequestParams) ([]*models.ApiKeyResponse, error) {
+	log := log.New("service", "GetList")
+	queries := params.ToMap()
+	records, err := s.repo.GetList(ctx, queries)
+	if err != nil {
+		log.Error("service.repo.GetList: %v", err)
+		return nil, err
+	}
+	return (&entity.ApiKey{}).ExportList(records), nil
+}
+
+func (s *service) Create(ctx context.Context, userId int64, params *models.ApiKeySaveRequest) (*models.ApiKeyResponse, error) {
+	log := log.New("service", "Create")
+	obj := &entity.ApiKey{}
+	obj.ParseForCreate(params, userId)
+	res, err := s.repo.Create(ctx, obj)
+	if err != nil {
+		log.Error("service.repo.Create: %v", err)
+		return nil, err
+	}
+	return res.Export(), nil
+}
+
+func (s *service) Update(ctx context.Context, userId int64, params *models.ApiKeySaveRequest) (*models.ApiKeyResponse, error) {
+	log := log.New("service", "Update")
+	obj := &entity.ApiKey{}
+	obj.ParseForUpdate(params, userId)
+	res, err := s.repo.Update(ctx, obj)
+	if err != nil {
+		log.Error("service.repo.Update: %v", err)
+		return nil, err
+	}
+	return res.Export(), nil
+}
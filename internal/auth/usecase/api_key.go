@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"medioa/constants"
+	"medioa/internal/auth/models"
+	"medioa/internal/auth/provider"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) CreateApiKey(ctx context.Context, userId int64, params *models.CreateApiKeyRequest) (*models.CreateApiKeyResponse, error) {
+	log := log.New("usecase", "CreateApiKey")
+
+	// validation
+	if userId == 0 {
+		return nil, fmt.Errorf("authorization is required")
+	}
+	principal := models.GetPrincipal(ctx)
+
+	// only a static token or an admin key can mint keys
+	if !canManageAllKeys(principal) {
+		return nil, fmt.Errorf("scope %s is required", constants.AUTH_SCOPE_ADMIN)
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	if len(params.Scopes) == 0 {
+		return nil, fmt.Errorf("scopes are required")
+	}
+	scopes := make([]string, 0, len(params.Scopes))
+	for _, scope := range params.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(constants.AUTH_SCOPES, scope) {
+			return nil, fmt.Errorf("scope %s is invalid", scope)
+		}
+		// a key can't grant more than its creator holds
+		if !principal.HasScope(scope) {
+			return nil, fmt.Errorf("scope %s is not granted", scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if !params.ExpireAt.IsZero() && !params.ExpireAt.After(time.Now()) {
+		return nil, fmt.Errorf("expire at must be in the future")
+	}
+
+	// a key can't outlive the key that created it
+	expiredAt := params.ExpireAt
+	if !principal.ExpiredAt.IsZero() && (expiredAt.IsZero() || expiredAt.After(principal.ExpiredAt)) {
+		expiredAt = principal.ExpiredAt
+	}
+
+	// end validation
+
+	key, err := generateApiKey()
+	if err != nil {
+		log.Error("usecase.generateApiKey", err)
+		return nil, err
+	}
+
+	_id := uuid.New().String()
+	if _, err := u.authSv.Create(ctx, userId, &models.ApiKeySaveRequest{
+		UUID:      _id,
+		Name:      name,
+		Prefix:    key[:constants.API_KEY_DISPLAY_SIZE],
+		KeyHash:   provider.HashApiKey(key),
+		Scopes:    scopes,
+		ParentId:  principal.KeyId,
+		ExpiredAt: expiredAt,
+	}); err != nil {
+		log.Error("usecase.authSv.Create", err)
+		return nil, err
+	}
+
+	return &models.CreateApiKeyResponse{
+		KeyId:     _id,
+		Name:      name,
+		Key:       key,
+		Scopes:    scopes,
+		ExpiredAt: timeOrNil(expiredAt),
+	}, nil
+}
+
+func (u *usecase) ListApiKeys(ctx context.Context, userId int64, params *models.ListApiKeyRequest) (*models.ListApiKeyResponse, error) {
+	log := log.New("usecase", "ListApiKeys")
+
+	// validation
+	if userId == 0 {
+		return nil, fmt.Errorf("authorization is required")
+	}
+	principal := models.GetPrincipal(ctx)
+	// end validation
+
+	query := &models.ApiKeyRequestParams{
+		CreatedBy: userId,
+	}
+	if !params.IncludeRevoked {
+		isRevoked := false
+		query.IsRevoked = &isRevoked
+	}
+	keys, err := u.authSv.GetList(ctx, query)
+	if err != nil {
+		log.Error("usecase.authSv.GetList", err)
+		return nil, err
+	}
+
+	// other keys only see themselves and the keys they created
+	if !canManageAllKeys(principal) {
+		keys = descendantKeys(keys, principal.KeyId)
+	}
+
+	res := &models.ListApiKeyResponse{
+		Keys: make([]*models.ApiKey, 0, len(keys)),
+	}
+	for _, key := range keys {
+		res.Keys = append(res.Keys, &models.ApiKey{
+			KeyId:     key.UUID,
+			Name:      key.Name,
+			Prefix:    key.Prefix,
+			Scopes:    key.Scopes,
+			ParentId:  key.ParentId,
+			ExpiredAt: timeOrNil(key.ExpiredAt),
+			RevokedAt: key.RevokedAt,
+			CreatedAt: key.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (u *usecase) RevokeApiKey(ctx context.Context, userId int64, params *models.RevokeApiKeyRequest) (*models.RevokeApiKeyResponse, error) {
+	log := log.New("usecase", "RevokeApiKey")
+
+	// validation
+	if userId == 0 {
+		return nil, fmt.Errorf("authorization is required")
+	}
+
+	// only the owner can revoke a key
+	key, err := u.authSv.GetOne(ctx, &models.ApiKeyRequestParams{
+		UUID:      params.KeyId,
+		CreatedBy: userId,
+	})
+	if err != nil {
+		log.Error("usecase.authSv.GetOne", err)
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("api key not found")
+	}
+
+	// other keys only revoke themselves and the keys they created
+	if principal := models.GetPrincipal(ctx); !canManageAllKeys(principal) {
+		ok, err := u.isDescendantKey(ctx, userId, key, principal.KeyId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("api key not found")
+		}
+	}
+
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("api key already revoked")
+	}
+
+	// end validation
+
+	revokedAt := time.Now()
+	if _, err := u.authSv.Update(ctx, userId, &models.ApiKeySaveRequest{
+		UUID:      key.UUID,
+		RevokedAt: &revokedAt,
+	}); err != nil {
+		log.Error("usecase.authSv.Update", err)
+		return nil, err
+	}
+	if err := u.revokeChildKeys(ctx, userId, key.UUID, revokedAt); err != nil {
+		return nil, err
+	}
+
+	return &models.RevokeApiKeyResponse{
+		KeyId:     key.UUID,
+		RevokedAt: revokedAt,
+	}, nil
+}
+
+// revokeChildKeys revokes every key created with the revoked one, and theirs in turn
+func (u *usecase) revokeChildKeys(ctx context.Context, userId int64, keyId string, revokedAt time.Time) error {
+	log := log.New("usecase", "revokeChildKeys")
+
+	isRevoked := false
+	parentIds := []string{keyId}
+	for len(parentIds) > 0 {
+		children, err := u.authSv.GetList(ctx, &models.ApiKeyRequestParams{
+			ParentId:  parentIds[0],
+			IsRevoked: &isRevoked,
+		})
+		if err != nil {
+			log.Error("usecase.authSv.GetList", err)
+			return err
+		}
+		parentIds = parentIds[1:]
+
+		for _, child := range children {
+			if _, err := u.authSv.Update(ctx, userId, &models.ApiKeySaveRequest{
+				UUID:      child.UUID,
+				RevokedAt: &revokedAt,
+			}); err != nil {
+				log.Error("usecase.authSv.Update", err)
+				return err
+			}
+			parentIds = append(parentIds, child.UUID)
+		}
+	}
+	return nil
+}
+
+// isDescendantKey reports whether a key is the ancestor key or was created by it, directly or not
+func (u *usecase) isDescendantKey(ctx context.Context, userId int64, key *models.ApiKeyResponse, ancestorId string) (bool, error) {
+	log := log.New("usecase", "isDescendantKey")
+
+	if ancestorId == "" {
+		return false, nil
+	}
+	for key.UUID != ancestorId {
+		if key.ParentId == "" {
+			return false, nil
+		}
+		parent, err := u.authSv.GetOne(ctx, &models.ApiKeyRequestParams{
+			UUID:      key.ParentId,
+			CreatedBy: userId,
+		})
+		if err != nil {
+			log.Error("usecase.authSv.GetOne", err)
+			return false, err
+		}
+		if parent == nil {
+			return false, nil
+		}
+		key = parent
+	}
+	return true, nil
+}
+
+// canManageAllKeys reports whether the caller manages every key of its user,
+// a static token or an admin key
+func canManageAllKeys(principal *models.Principal) bool {
+	return principal.Method == models.AUTH_METHOD_TOKEN || principal.HasScope(constants.AUTH_SCOPE_ADMIN)
+}
+
+// descendantKeys keeps the ancestor key and the keys created by it, directly or not
+func descendantKeys(keys []*models.ApiKeyResponse, ancestorId string) []*models.ApiKeyResponse {
+	if ancestorId == "" {
+		return []*models.ApiKeyResponse{}
+	}
+	parentIds := make(map[string]string, len(keys))
+	for _, key := range keys {
+		parentIds[key.UUID] = key.ParentId
+	}
+
+	res := make([]*models.ApiKeyResponse, 0)
+	for _, key := range keys {
+		// a parent is created before its children, the chain is at most len(keys) long
+		id := key.UUID
+		for range len(keys) {
+			if id == ancestorId || id == "" {
+				break
+			}
+			id = parentIds[id]
+		}
+		if id == ancestorId {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// generateApiKey returns a random key, the prefix lets the provider recognize it
+func generateApiKey() (string, error) {
+	buf := make([]byte, constants.API_KEY_SIZE)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return constants.API_KEY_PREFIX + hex.EncodeToString(buf), nil
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"medioa/internal/auth/models"
+	"reflect"
+	"testing"
+)
+
+func TestDescendantKeys(t *testing.T) {
+	// admin -> upload -> download, other is another root key
+	keys := []*models.ApiKeyResponse{
+		{UUID: "admin"},
+		{UUID: "upload", ParentId: "admin"},
+		{UUID: "download", ParentId: "upload"},
+		{UUID: "other"},
+	}
+	tests := []struct {
+		name       string
+		ancestorId string
+		want       []string
+	}{
+		{name: "root key sees its tree", ancestorId: "admin", want: []string{"admin", "upload", "download"}},
+		{name: "child key sees itself and below", ancestorId: "upload", want: []string{"upload", "download"}},
+		{name: "leaf key sees itself", ancestorId: "download", want: []string{"download"}},
+		{name: "unknown key sees nothing", ancestorId: "missing", want: []string{}},
+		{name: "no key sees nothing", ancestorId: "", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, key := range descendantKeys(keys, tt.ancestorId) {
+				got = append(got, key.UUID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("descendantKeys(%q) = %v, want %v", tt.ancestorId, got, tt.want)
+			}
+		})
+	}
+}
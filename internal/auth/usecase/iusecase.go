@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"medioa/internal/auth/models"
+)
+
+type IUsecase interface {
+	CreateApiKey(ctx context.Context, userId int64, params *models.CreateApiKeyRequest) (*models.CreateApiKeyResponse, error)
+	ListApiKeys(ctx context.Context, userId int64, params *models.ListApiKeyRequest) (*models.ListApiKeyResponse, error)
+	RevokeApiKey(ctx context.Context, userId int64, params *models.RevokeApiKeyRequest) (*models.RevokeApiKeyResponse, error)
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"medioa/config"
+	authSv "medioa/internal/auth/service"
+)
+
+type usecase struct {
+	cfg    *config.Config
+	authSv authSv.IService
+}
+
+func InitUsecase(cfg *config.Config, authSv authSv.IService) IUsecase {
+	return &usecase{
+		cfg:    cfg,
+		authSv: authSv,
+	}
+}
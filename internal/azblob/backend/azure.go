@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"medioa/config"
+	"medioa/internal/azblob/models"
+	commonModel "medioa/models"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+type azure struct {
+	cfg *config.Config
+	lib *commonModel.Lib
+}
+
+func InitAzure(cfg *config.Config, lib *commonModel.Lib) IBackend {
+	return &azure{
+		cfg: cfg,
+		lib: lib,
+	}
+}
+
+// https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/storage/azblob/blockblob/examples_test.go
+func (a *azure) UploadURL(ctx context.Context, blobName string, url string) error {
+	opts := &blockblob.UploadBlobFromURLOptions{
+		// Metadata: map[string]*string{},
+	}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	if _, err := blobClient.UploadBlobFromURL(ctx, url, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *azure) UploadStream(ctx context.Context, blobName string, reader io.Reader) error {
+	// buffers are bounded to BlockSize * Concurrency
+	opts := &blockblob.UploadStreamOptions{
+		BlockSize:   a.cfg.Upload.StreamBlockMB << 20,
+		Concurrency: int(a.cfg.Upload.StreamParallel),
+		// Metadata: map[string]*string{},
+	}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	if _, err := blobClient.UploadStream(ctx, reader, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateUpload has nothing to create, uncommitted blocks are kept by the blob itself
+func (a *azure) CreateUpload(ctx context.Context, blobName string) (string, error) {
+	return "", nil
+}
+
+func (a *azure) MinBlockSize() int64 {
+	return 0
+}
+
+func (a *azure) StageBlock(ctx context.Context, blobName string, uploadId string, blockId string, reader io.ReadSeekCloser) error {
+	opts := &blockblob.StageBlockOptions{}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	if _, err := blobClient.StageBlock(ctx, blockId, reader, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *azure) CommitBlockList(ctx context.Context, blobName string, uploadId string, blockIds []string) (*models.CommitChunkRsponse, error) {
+	opts := &blockblob.CommitBlockListOptions{}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	if _, err := blobClient.CommitBlockList(ctx, blockIds, opts); err != nil {
+		return nil, err
+	}
+
+	var fileSize int64
+	var totalBlock int64
+	getBlock, err := blobClient.GetBlockList(ctx, blockblob.BlockListTypeCommitted, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range getBlock.BlockList.CommittedBlocks {
+		fileSize += *block.Size
+		totalBlock++
+	}
+
+	return &models.CommitChunkRsponse{
+		FileSize:   fileSize,
+		TotalBlock: totalBlock,
+	}, nil
+}
+
+// https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/storage/azblob/sas/examples_test.go
+func (a *azure) GetSASURL(ctx context.Context, blobName string, expiry time.Time) (string, error) {
+	blobURL := fmt.Sprintf("%s/%s/%s", a.cfg.AzBlob.Host, a.cfg.Storage.Container, blobName)
+	blobCli, err := blob.NewClientWithSharedKeyCredential(blobURL, a.lib.Blob.Credential, nil)
+	if err != nil {
+		return "", err
+	}
+
+	permissions := sas.BlobPermissions{Read: true}
+	sasURL, err := blobCli.GetSASURL(permissions, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return sasURL, nil
+}
+
+// GetUploadURL signs a create-only SAS, an existing blob can't be overwritten with it
+func (a *azure) GetUploadURL(ctx context.Context, blobName string, expiry time.Time) (*models.UploadSASResponse, error) {
+	blobURL := fmt.Sprintf("%s/%s/%s", a.cfg.AzBlob.Host, a.cfg.Storage.Container, blobName)
+	blobCli, err := blob.NewClientWithSharedKeyCredential(blobURL, a.lib.Blob.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := sas.BlobPermissions{Create: true}
+	sasURL, err := blobCli.GetSASURL(permissions, expiry, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UploadSASResponse{
+		Url:     sasURL,
+		Method:  http.MethodPut,
+		Headers: map[string]string{"x-ms-blob-type": "BlockBlob"},
+	}, nil
+}
+
+func (a *azure) Download(ctx context.Context, blobName string) (io.ReadCloser, error) {
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	res, err := blobClient.DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// DownloadRange reads the blob from offset to its end
+func (a *azure) DownloadRange(ctx context.Context, blobName string, offset int64) (io.ReadCloser, error) {
+	opts := &blob.DownloadStreamOptions{Range: blob.HTTPRange{Offset: offset}}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	res, err := blobClient.DownloadStream(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Delete removes the blob with its snapshots, a missing blob is not an error.
+func (a *azure) Delete(ctx context.Context, blobName string) error {
+	opts := &blob.DeleteOptions{DeleteSnapshots: to.Ptr(blob.DeleteSnapshotsOptionTypeInclude)}
+	blobClient := a.lib.Blob.Container.NewBlockBlobClient(blobName)
+	_, err := blobClient.Delete(ctx, opts)
+	if err == nil {
+		return nil
+	}
+	if !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return err
+	}
+
+	// blob was never committed, commit an empty block list to discard the staged blocks
+	if _, err := blobClient.CommitBlockList(ctx, []string{}, nil); err != nil {
+		return err
+	}
+	if _, err := blobClient.Delete(ctx, opts); err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (a *azure) GetURL(blobName string) string {
+	return path.Join(a.cfg.AzBlob.Host, a.cfg.Storage.Container, blobName)
+}
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medioa/pkg/xhttp"
+	"net/http"
+)
+
+// uploadFromURL fetches the url through medioa and streams it into the backend,
+// used by backends that cannot copy from a url server-side. Private addresses and
+// bodies over maxSizeMB are refused.
+func uploadFromURL(ctx context.Context, backend IBackend, blobName string, url string, maxSizeMB int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := xhttp.NewSafeClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	maxSize := maxSizeMB << 20
+	if res.ContentLength > maxSize {
+		return fmt.Errorf("file size too large (max: %dMB)", maxSizeMB)
+	}
+	body := http.MaxBytesReader(nil, res.Body, maxSize)
+	if err := backend.UploadStream(ctx, blobName, body); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return fmt.Errorf("file size too large (max: %dMB)", maxSizeMB)
+		}
+		return err
+	}
+	return nil
+}
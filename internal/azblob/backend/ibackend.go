@@ -0,0 +1,23 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"medioa/internal/azblob/models"
+	"time"
+)
+
+type IBackend interface {
+	UploadURL(ctx context.Context, blobName string, url string) error
+	UploadStream(ctx context.Context, blobName string, reader io.Reader) error
+	CreateUpload(ctx context.Context, blobName string) (string, error)
+	MinBlockSize() int64
+	StageBlock(ctx context.Context, blobName string, uploadId string, blockId string, reader io.ReadSeekCloser) error
+	CommitBlockList(ctx context.Context, blobName string, uploadId string, blockIds []string) (*models.CommitChunkRsponse, error)
+	GetSASURL(ctx context.Context, blobName string, expiry time.Time) (string, error)
+	GetUploadURL(ctx context.Context, blobName string, expiry time.Time) (*models.UploadSASResponse, error)
+	Download(ctx context.Context, blobName string) (io.ReadCloser, error)
+	DownloadRange(ctx context.Context, blobName string, offset int64) (io.ReadCloser, error)
+	Delete(ctx context.Context, blobName string) error
+	GetURL(blobName string) string
+}
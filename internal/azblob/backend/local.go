@@ -0,0 +1,208 @@
+package backend
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/azblob/models"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// local stores blobs on disk under <LocalPath>/<Container>/<blobName>,
+// staged blocks are kept under <LocalPath>/<Container>/.blocks until committed.
+type local struct {
+	cfg *config.Config
+}
+
+func InitLocal(cfg *config.Config) IBackend {
+	return &local{
+		cfg: cfg,
+	}
+}
+
+func (l *local) UploadURL(ctx context.Context, blobName string, url string) error {
+	return uploadFromURL(ctx, l, blobName, url, l.cfg.Upload.MaxSizeMB)
+}
+
+func (l *local) UploadStream(ctx context.Context, blobName string, reader io.Reader) error {
+	return writeFile(LocalFilePath(l.cfg, blobName), func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	})
+}
+
+// CreateUpload has nothing to create, staged blocks are kept under the blob name
+func (l *local) CreateUpload(ctx context.Context, blobName string) (string, error) {
+	return "", nil
+}
+
+func (l *local) MinBlockSize() int64 {
+	return 0
+}
+
+func (l *local) StageBlock(ctx context.Context, blobName string, uploadId string, blockId string, reader io.ReadSeekCloser) error {
+	return writeFile(l.blockPath(blobName, blockId), func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	})
+}
+
+func (l *local) CommitBlockList(ctx context.Context, blobName string, uploadId string, blockIds []string) (*models.CommitChunkRsponse, error) {
+	var fileSize int64
+	err := writeFile(LocalFilePath(l.cfg, blobName), func(w io.Writer) error {
+		for _, blockId := range blockIds {
+			n, err := copyFile(w, l.blockPath(blobName, blockId))
+			if err != nil {
+				return err
+			}
+			fileSize += n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = os.RemoveAll(l.blockDir(blobName))
+
+	return &models.CommitChunkRsponse{
+		FileSize:   fileSize,
+		TotalBlock: int64(len(blockIds)),
+	}, nil
+}
+
+func (l *local) GetSASURL(ctx context.Context, blobName string, expiry time.Time) (string, error) {
+	se := expiry.Unix()
+	query := url.Values{}
+	query.Set("se", fmt.Sprint(se))
+	query.Set("sig", SignLocalBlob(l.cfg.Secret.SecretKey, blobName, se))
+	return l.GetURL(blobName) + "?" + query.Encode(), nil
+}
+
+func (l *local) GetUploadURL(ctx context.Context, blobName string, expiry time.Time) (*models.UploadSASResponse, error) {
+	se := expiry.Unix()
+	query := url.Values{}
+	query.Set("se", fmt.Sprint(se))
+	query.Set("sig", SignLocalBlobUpload(l.cfg.Secret.SecretKey, blobName, se))
+	return &models.UploadSASResponse{
+		Url:    l.GetURL(blobName) + "?" + query.Encode(),
+		Method: http.MethodPut,
+	}, nil
+}
+
+func (l *local) Download(ctx context.Context, blobName string) (io.ReadCloser, error) {
+	return os.Open(LocalFilePath(l.cfg, blobName))
+}
+
+// DownloadRange reads the blob from offset to its end
+func (l *local) DownloadRange(ctx context.Context, blobName string, offset int64) (io.ReadCloser, error) {
+	file, err := os.Open(LocalFilePath(l.cfg, blobName))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the blob and any staged blocks, a missing blob is not an error.
+func (l *local) Delete(ctx context.Context, blobName string) error {
+	if err := os.Remove(LocalFilePath(l.cfg, blobName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(l.blockDir(blobName))
+}
+
+func (l *local) GetURL(blobName string) string {
+	filePath := strings.ReplaceAll(constants.BLOB_ENDPOINT_LOCAL_DOWNLOAD, "/*file_name", "")
+	return fmt.Sprintf("%s/blob%s/%s", l.cfg.App.Host, filePath, blobName)
+}
+
+func (l *local) blockDir(blobName string) string {
+	return LocalFilePath(l.cfg, path.Join(".blocks", blobName))
+}
+
+func (l *local) blockPath(blobName, blockId string) string {
+	return filepath.Join(l.blockDir(blobName), hex.EncodeToString([]byte(blockId)))
+}
+
+// LocalFilePath resolves a blob name to its path on disk, the blob name is cleaned
+// so it can never escape the container directory.
+func LocalFilePath(cfg *config.Config, blobName string) string {
+	blobName = path.Clean("/" + blobName)
+	return filepath.Join(cfg.Storage.LocalPath, cfg.Storage.Container, filepath.FromSlash(blobName))
+}
+
+// SignLocalBlob signs a blob name with its expiry (unix seconds), used as the local equivalent of SAS.
+func SignLocalBlob(secretKey, blobName string, expiry int64) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(fmt.Sprintf("%s\n%d", path.Clean("/"+blobName), expiry)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignLocalBlobUpload signs a blob name for upload, it never matches a download signature.
+func SignLocalBlobUpload(secretKey, blobName string, expiry int64) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(fmt.Sprintf("upload\n%s\n%d", path.Clean("/"+blobName), expiry)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// UploadLocalOnce writes a blob with a signed upload url, it fails with os.ErrExist
+// instead of replacing a blob that was already written.
+func UploadLocalOnce(cfg *config.Config, blobName string, reader io.Reader) error {
+	return writeNewFile(LocalFilePath(cfg, blobName), func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	})
+}
+
+// writeFile writes to a temp file next to dst then renames it, so readers never see a partial blob.
+func writeFile(dst string, write func(w io.Writer) error) error {
+	return writeTempFile(dst, write, os.Rename)
+}
+
+// writeNewFile is writeFile that links the temp file instead, linking fails when dst exists.
+func writeNewFile(dst string, write func(w io.Writer) error) error {
+	return writeTempFile(dst, write, os.Link)
+}
+
+func writeTempFile(dst string, write func(w io.Writer) error, place func(src, dst string) error) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return place(tmp.Name(), dst)
+}
+
+func copyFile(w io.Writer, src string) (int64, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
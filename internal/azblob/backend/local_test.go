@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"errors"
+	"medioa/config"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUploadLocalOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		blobName string
+		existing string // written before the upload, none when empty
+		content  string
+		want     string
+		wantErr  error
+	}{
+		{name: "new blob", blobName: "a/b.png", content: "new", want: "new"},
+		{name: "existing blob is kept", blobName: "a/b.png", existing: "first", content: "second", want: "first", wantErr: os.ErrExist},
+		{name: "path can't escape the container", blobName: "../../c.png", content: "new", want: "new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Storage: config.StorageConfig{LocalPath: t.TempDir(), Container: "media"}}
+			if tt.existing != "" {
+				if err := UploadLocalOnce(cfg, tt.blobName, strings.NewReader(tt.existing)); err != nil {
+					t.Fatalf("UploadLocalOnce() error = %v", err)
+				}
+			}
+
+			err := UploadLocalOnce(cfg, tt.blobName, strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UploadLocalOnce() error = %v, want %v", err, tt.wantErr)
+			}
+			got, err := os.ReadFile(LocalFilePath(cfg, tt.blobName))
+			if err != nil {
+				t.Fatalf("read blob: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("blob = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
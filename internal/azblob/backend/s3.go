@@ -0,0 +1,211 @@
+package backend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"medioa/config"
+	"medioa/internal/azblob/models"
+	commonModel "medioa/models"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// s3MinPartSize is the smallest part S3 accepts, except for the last one
+const s3MinPartSize = 5 << 20
+
+// s3 stores blobs in an S3-compatible bucket (AWS S3, MinIO, ...),
+// staged blocks are mapped to multipart upload parts (part number = chunk index + 1).
+// The multipart upload id is kept by the caller so every instance stages to the same upload.
+type s3 struct {
+	cfg *config.Config
+	lib *commonModel.Lib
+}
+
+func InitS3(cfg *config.Config, lib *commonModel.Lib) IBackend {
+	return &s3{
+		cfg: cfg,
+		lib: lib,
+	}
+}
+
+func (s *s3) UploadURL(ctx context.Context, blobName string, url string) error {
+	return uploadFromURL(ctx, s, blobName, url, s.cfg.Upload.MaxSizeMB)
+}
+
+func (s *s3) UploadStream(ctx context.Context, blobName string, reader io.Reader) error {
+	// unknown size, minio buffers whole parts: bounded to PartSize * NumThreads
+	opts := minio.PutObjectOptions{
+		PartSize:              uint64(s.cfg.Upload.StreamBlockMB << 20),
+		NumThreads:            uint(s.cfg.Upload.StreamParallel),
+		ConcurrentStreamParts: s.cfg.Upload.StreamParallel > 1,
+	}
+	if _, err := s.lib.S3.Client.PutObject(ctx, s.cfg.Storage.Container, blobName, reader, -1, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateUpload starts the multipart upload blocks of the blob are staged to
+func (s *s3) CreateUpload(ctx context.Context, blobName string) (string, error) {
+	return s.lib.S3.NewMultipartUpload(ctx, s.cfg.Storage.Container, blobName, minio.PutObjectOptions{})
+}
+
+func (s *s3) MinBlockSize() int64 {
+	return s3MinPartSize
+}
+
+func (s *s3) StageBlock(ctx context.Context, blobName string, uploadId string, blockId string, reader io.ReadSeekCloser) error {
+	if uploadId == "" {
+		return fmt.Errorf("no multipart upload found for %s", blobName)
+	}
+	partNumber, err := blockIdToPartNumber(blockId)
+	if err != nil {
+		return err
+	}
+
+	size, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	opts := minio.PutObjectPartOptions{}
+	if _, err := s.lib.S3.PutObjectPart(ctx, s.cfg.Storage.Container, blobName, uploadId, partNumber, reader, size, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *s3) CommitBlockList(ctx context.Context, blobName string, uploadId string, blockIds []string) (*models.CommitChunkRsponse, error) {
+	if uploadId == "" {
+		return nil, fmt.Errorf("no staged blocks found for %s", blobName)
+	}
+
+	uploaded, err := s.listParts(ctx, blobName, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileSize int64
+	parts := make([]minio.CompletePart, 0, len(blockIds))
+	for _, blockId := range blockIds {
+		partNumber, err := blockIdToPartNumber(blockId)
+		if err != nil {
+			return nil, err
+		}
+		part, ok := uploaded[partNumber]
+		if !ok {
+			return nil, fmt.Errorf("block %s was not staged", blockId)
+		}
+		fileSize += part.Size
+		parts = append(parts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	opts := minio.PutObjectOptions{}
+	if _, err := s.lib.S3.CompleteMultipartUpload(ctx, s.cfg.Storage.Container, blobName, uploadId, parts, opts); err != nil {
+		return nil, err
+	}
+
+	return &models.CommitChunkRsponse{
+		FileSize:   fileSize,
+		TotalBlock: int64(len(parts)),
+	}, nil
+}
+
+func (s *s3) GetSASURL(ctx context.Context, blobName string, expiry time.Time) (string, error) {
+	url, err := s.lib.S3.Client.PresignedGetObject(ctx, s.cfg.Storage.Container, blobName, time.Until(expiry), nil)
+	if err != nil {
+		return "", err
+	}
+	return url.String(), nil
+}
+
+// GetUploadURL signs a create-only put, If-None-Match is part of the signature so the
+// url can't overwrite an existing object
+func (s *s3) GetUploadURL(ctx context.Context, blobName string, expiry time.Time) (*models.UploadSASResponse, error) {
+	headers := http.Header{}
+	headers.Set("If-None-Match", "*")
+	url, err := s.lib.S3.Client.PresignHeader(ctx, http.MethodPut, s.cfg.Storage.Container, blobName, time.Until(expiry), nil, headers)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UploadSASResponse{
+		Url:     url.String(),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"If-None-Match": "*"},
+	}, nil
+}
+
+func (s *s3) Download(ctx context.Context, blobName string) (io.ReadCloser, error) {
+	obj, err := s.lib.S3.Client.GetObject(ctx, s.cfg.Storage.Container, blobName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// DownloadRange reads the object from offset to its end
+func (s *s3) DownloadRange(ctx context.Context, blobName string, offset int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if offset > 0 {
+		if err := opts.SetRange(offset, 0); err != nil {
+			return nil, err
+		}
+	}
+	obj, err := s.lib.S3.Client.GetObject(ctx, s.cfg.Storage.Container, blobName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Delete removes the object and aborts its pending multipart upload if any.
+func (s *s3) Delete(ctx context.Context, blobName string) error {
+	if err := s.lib.S3.Client.RemoveObject(ctx, s.cfg.Storage.Container, blobName, minio.RemoveObjectOptions{}); err != nil {
+		return err
+	}
+	return s.lib.S3.Client.RemoveIncompleteUpload(ctx, s.cfg.Storage.Container, blobName)
+}
+
+func (s *s3) GetURL(blobName string) string {
+	return fmt.Sprintf("%s/%s/%s", s.lib.S3.Client.EndpointURL(), s.cfg.Storage.Container, blobName)
+}
+
+func (s *s3) listParts(ctx context.Context, blobName, uploadId string) (map[int]minio.ObjectPart, error) {
+	parts := make(map[int]minio.ObjectPart)
+	marker := 0
+	for {
+		res, err := s.lib.S3.ListObjectParts(ctx, s.cfg.Storage.Container, blobName, uploadId, marker, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range res.ObjectParts {
+			parts[part.PartNumber] = part
+		}
+		if !res.IsTruncated {
+			return parts, nil
+		}
+		marker = res.NextPartNumberMarker
+	}
+}
+
+func blockIdToPartNumber(blockId string) (int, error) {
+	buf, err := base64.StdEncoding.DecodeString(blockId)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block id %s", blockId)
+	}
+	idx, n := binary.Varint(buf)
+	if n <= 0 || idx < 0 {
+		return 0, fmt.Errorf("invalid block id %s", blockId)
+	}
+	return int(idx) + 1, nil
+}
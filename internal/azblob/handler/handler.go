@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/azblob/backend"
+	commonModel "medioa/models"
+	"medioa/pkg/xhttp"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	cfg *config.Config
+	lib *commonModel.Lib
+}
+
+func InitHandler(cfg *config.Config, lib *commonModel.Lib) IHandler {
+	return Handler{
+		cfg: cfg,
+		lib: lib,
+	}
+}
+
+func (h Handler) MapRoutes(group *gin.RouterGroup) {
+	if h.cfg.Storage.Backend == config.STORAGE_BACKEND_LOCAL {
+		group.GET(constants.BLOB_ENDPOINT_LOCAL_DOWNLOAD, h.DownloadLocal)
+		group.PUT(constants.BLOB_ENDPOINT_LOCAL_UPLOAD, h.UploadLocal)
+	}
+}
+
+// DownloadLocal godoc
+//
+//	@Summary		Download local blob
+//	@Description	Serve a blob from the local storage backend with a signed url
+//	@Tags			Blob
+//	@Produce		octet-stream
+//	@Param			file_name	path	string	true	"blob name"
+//	@Param			se			query	int64	true	"expiry (unix seconds)"
+//	@Param			sig			query	string	true	"signature"
+//	@Success		200
+//	@Router			/blob/local/{file_name} [get]
+func (h Handler) DownloadLocal(ctx *gin.Context) {
+	fileName := ctx.Param("file_name")
+	sig := ctx.Query("sig")
+	expiry, err := strconv.ParseInt(ctx.Query("se"), 10, 64)
+	if err != nil {
+		xhttp.BadRequest(ctx, fmt.Errorf("invalid expiry"))
+		return
+	}
+
+	if time.Now().Unix() > expiry {
+		xhttp.BadRequest(ctx, fmt.Errorf("url expired"))
+		return
+	}
+
+	expected := backend.SignLocalBlob(h.cfg.Secret.SecretKey, fileName, expiry)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		xhttp.BadRequest(ctx, fmt.Errorf("signature is invalid"))
+		return
+	}
+
+	filePath := backend.LocalFilePath(h.cfg, fileName)
+	if _, err := os.Stat(filePath); err != nil {
+		xhttp.BadRequest(ctx, fmt.Errorf("file not found"))
+		return
+	}
+
+	ctx.File(filePath)
+}
+
+// UploadLocal godoc
+//
+//	@Summary		Upload local blob
+//	@Description	Write a blob to the local storage backend with a signed upload url, an existing blob is never overwritten and the size is limited to the max upload size
+//	@Tags			Blob
+//	@Accept			octet-stream
+//	@Produce		json
+//	@Param			file_name	path	string	true	"blob name"
+//	@Param			se			query	int64	true	"expiry (unix seconds)"
+//	@Param			sig			query	string	true	"signature"
+//	@Success		201
+//	@Router			/blob/local/{file_name} [put]
+func (h Handler) UploadLocal(ctx *gin.Context) {
+	fileName := ctx.Param("file_name")
+	sig := ctx.Query("sig")
+	expiry, err := strconv.ParseInt(ctx.Query("se"), 10, 64)
+	if err != nil {
+		xhttp.BadRequest(ctx, fmt.Errorf("invalid expiry"))
+		return
+	}
+
+	if time.Now().Unix() > expiry {
+		xhttp.BadRequest(ctx, fmt.Errorf("url expired"))
+		return
+	}
+
+	expected := backend.SignLocalBlobUpload(h.cfg.Secret.SecretKey, fileName, expiry)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		xhttp.BadRequest(ctx, fmt.Errorf("signature is invalid"))
+		return
+	}
+
+	// the url stays valid until expiry, a second upload must not replace a finalized blob
+	maxSize := h.cfg.Upload.MaxSizeMB
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize<<20)
+	if err := backend.UploadLocalOnce(h.cfg, strings.TrimPrefix(fileName, "/"), body); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, os.ErrExist):
+			xhttp.BadRequest(ctx, fmt.Errorf("file already exists"))
+		case errors.As(err, &maxBytesErr):
+			xhttp.BadRequest(ctx, fmt.Errorf("file size too large (max: %dMB)", maxSize))
+		default:
+			xhttp.BadRequest(ctx, err)
+		}
+		return
+	}
+
+	xhttp.Created(ctx, nil)
+}
@@ -2,20 +2,36 @@ package init
 
 import (
 	"medioa/config"
+	"medioa/internal/azblob/backend"
+	"medioa/internal/azblob/handler"
 	"medioa/internal/azblob/service"
 	commonModel "medioa/models"
 )
 
 type Init struct {
+	Backend backend.IBackend
 	Service service.IService
+	Handler handler.IHandler
 }
 
 func NewInit(
 	cfg *config.Config,
 	lib *commonModel.Lib,
 ) *Init {
-	service := service.InitService(cfg, lib)
+	var blobBackend backend.IBackend
+	switch cfg.Storage.Backend {
+	case config.STORAGE_BACKEND_S3:
+		blobBackend = backend.InitS3(cfg, lib)
+	case config.STORAGE_BACKEND_LOCAL:
+		blobBackend = backend.InitLocal(cfg)
+	default:
+		blobBackend = backend.InitAzure(cfg, lib)
+	}
+	service := service.InitService(cfg, lib, blobBackend)
+	handler := handler.InitHandler(cfg, lib)
 	return &Init{
+		Backend: blobBackend,
 		Service: service,
+		Handler: handler,
 	}
 }
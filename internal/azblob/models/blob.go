@@ -1,7 +1,9 @@
 package models
 
 import (
+	"io"
 	"medioa/pkg/xtype"
+	"time"
 )
 
 type UploadResponse struct {
@@ -9,9 +11,13 @@ type UploadResponse struct {
 	Token    string
 	Ext      string
 	FileName string
+	Sha256   string
+	FileSize int64
+	MimeType string
 }
 
 type UploadURLRequest struct {
+	SessionId string
 	SecretId  string
 	URL       string
 }
@@ -22,36 +28,75 @@ type UploadBlobRequest struct {
 	File      xtype.File
 }
 
+type UploadStreamRequest struct {
+	SessionId string
+	SecretId  string
+	FileName  string
+	Size      int64 // -1 when unknown
+	Reader    io.Reader
+}
 
 type UploadChunkRequest struct {
 	SessionId   string
 	SecretId    string
 	Token       string
+	UploadId    string
 	FileName    string
 	ChunkIndex  int64
 	TotalChunks int64
 	Chunk       xtype.File
+	Hash        bool // the chunk follows the hashed ones, it is folded into HashState
+	HashState   []byte
 }
 
 type UploadChunkResponse struct {
-	Url      string
+	Url       string
+	Token     string
+	UploadId  string
+	BlockId   string
+	Ext       string
+	FileName  string
+	HashState []byte // sha256 state after the block, set when it was hashed
+}
+
+type CreateUploadRequest struct {
+	SecretId string
 	Token    string
-	BlockId  string
 	Ext      string
-	FileName string
+}
+
+type CreateUploadResponse struct {
+	UploadId string
+}
+
+type StageBlockRequest struct {
+	SecretId   string
+	Token      string
+	UploadId   string
+	Ext        string
+	ChunkIndex int64
+	Final      bool // the last block may be smaller than the backend min block size
+	Hash       bool // the block follows the hashed ones, it is folded into HashState
+	HashState  []byte
+	Reader     io.ReadSeekCloser
 }
 
 type CommitChunkRequest struct {
-	SessionId string
-	SecretId  string
-	Token     string
-	FileName  string
-	BlockIds  []string
+	SessionId  string
+	SecretId   string
+	Token      string
+	UploadId   string
+	FileName   string
+	BlockIds   []string
+	HashState  []byte // sha256 state of the first HashedSize bytes, only the rest is read back
+	HashedSize int64
 }
 
 type CommitChunkRsponse struct {
 	TotalBlock int64
 	FileSize   int64
+	FileName   string
+	Sha256     string
 }
 
 type DownloadSASRequest struct {
@@ -61,3 +106,36 @@ type DownloadSASRequest struct {
 type DownloadSASResponse struct {
 	Url string
 }
+
+type UploadSASRequest struct {
+	SecretId string
+	FileName string
+}
+
+type UploadSASResponse struct {
+	Url       string
+	Method    string
+	Headers   map[string]string
+	Token     string
+	Ext       string
+	FileName  string
+	ExpiredAt time.Time
+}
+
+type InspectBlobRequest struct {
+	FileName string
+}
+
+type InspectBlobResponse struct {
+	FileSize int64
+	MimeType string
+	Sha256   string
+}
+
+type ReadBlobRequest struct {
+	FileName string
+}
+
+type DeleteBlobRequest struct {
+	FileName string
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/zRedShift/mimemagic"
+)
+
+// blobInfo describes the content of a blob, collected while it is streamed
+type blobInfo struct {
+	sha256   string
+	size     int64
+	mimeType string
+}
+
+// countWriter counts the bytes written to it
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// inspectStream sniffs the mime type of a stream by content, then hands the whole stream to consume
+// while computing its checksum and size.
+func inspectStream(reader io.Reader, consume func(reader io.Reader) error) (*blobInfo, error) {
+	var head bytes.Buffer
+	mediaType, err := mimemagic.MatchReader(io.TeeReader(reader, &head), "")
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	counter := &countWriter{}
+	if err := consume(io.TeeReader(io.MultiReader(&head, reader), io.MultiWriter(hash, counter))); err != nil {
+		return nil, err
+	}
+
+	return &blobInfo{
+		sha256:   hex.EncodeToString(hash.Sum(nil)),
+		size:     counter.n,
+		mimeType: mediaType.MediaType(),
+	}, nil
+}
+
+// parseUploadURL only accepts absolute http(s) urls
+func parseUploadURL(rawURL string) (*url.URL, error) {
+	url, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if url.Scheme != "http" && url.Scheme != "https" {
+		return nil, fmt.Errorf("url scheme is invalid: %s", url.Scheme)
+	}
+	if url.Host == "" {
+		return nil, fmt.Errorf("url host is required")
+	}
+	return url, nil
+}
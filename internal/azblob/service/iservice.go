@@ -2,16 +2,25 @@ package service
 
 import (
 	"context"
+	"io"
 	"medioa/internal/azblob/models"
 )
 
 type IService interface {
 	UploadPublicURL(ctx context.Context, req *models.UploadURLRequest) (*models.UploadResponse, error)
 	UploadPublicBlob(ctx context.Context, req *models.UploadBlobRequest) (*models.UploadResponse, error)
+	UploadStream(ctx context.Context, req *models.UploadStreamRequest) (*models.UploadResponse, error)
 	UploadPublicChunk(ctx context.Context, req *models.UploadChunkRequest) (*models.UploadChunkResponse, error)
 	CommitPublicChunk(ctx context.Context, req *models.CommitChunkRequest) (*models.CommitChunkRsponse, error)
+	UploadPrivateURL(ctx context.Context, req *models.UploadURLRequest) (*models.UploadResponse, error)
 	UploadPrivateBlob(ctx context.Context, req *models.UploadBlobRequest) (*models.UploadResponse, error)
 	UploadPrivateChunk(ctx context.Context, req *models.UploadChunkRequest) (*models.UploadChunkResponse, error)
 	CommitPrivateChunk(ctx context.Context, req *models.CommitChunkRequest) (*models.CommitChunkRsponse, error)
+	CreateUpload(ctx context.Context, req *models.CreateUploadRequest) (*models.CreateUploadResponse, error)
+	StageBlock(ctx context.Context, req *models.StageBlockRequest) (*models.UploadChunkResponse, error)
 	DownloadSAS(ctx context.Context, req *models.DownloadSASRequest) (*models.DownloadSASResponse, error)
+	UploadSAS(ctx context.Context, req *models.UploadSASRequest) (*models.UploadSASResponse, error)
+	InspectBlob(ctx context.Context, req *models.InspectBlobRequest) (*models.InspectBlobResponse, error)
+	ReadBlob(ctx context.Context, req *models.ReadBlobRequest) (io.ReadCloser, error)
+	DeleteBlob(ctx context.Context, req *models.DeleteBlobRequest) error
 }
@@ -2,13 +2,22 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"medioa/config"
+	"medioa/internal/azblob/backend"
 	"medioa/internal/azblob/models"
 	commonModel "medioa/models"
+	"medioa/pkg/xhttp"
 	"medioa/pkg/xtype"
+	"net/http"
 	"net/url"
 	"path"
 	"time"
@@ -16,41 +25,42 @@ import (
 	"github.com/vukyn/kuery/log"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
-	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
-	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
-	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 	"github.com/vukyn/kuery/cryp"
 )
 
 type service struct {
-	cfg *config.Config
-	lib *commonModel.Lib
+	cfg     *config.Config
+	lib     *commonModel.Lib
+	backend backend.IBackend
+	streams chan struct{} // slots for streams relayed at once
 }
 
-func InitService(cfg *config.Config, lib *commonModel.Lib) IService {
+func InitService(cfg *config.Config, lib *commonModel.Lib, backend backend.IBackend) IService {
 	return &service{
-		cfg: cfg,
-		lib: lib,
+		cfg:     cfg,
+		lib:     lib,
+		backend: backend,
+		streams: make(chan struct{}, cfg.Upload.StreamMax),
 	}
 }
 
-// Upload to public Blob Storage (handle concurrent chunks)
+// Upload to public Blob Storage from url, copied by the backend or fetched through medioa
 // https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/storage/azblob/blockblob/examples_test.go
 func (s *service) UploadPublicURL(ctx context.Context, req *models.UploadURLRequest) (*models.UploadResponse, error) {
 	log := log.New("service", "UploadPublicURL")
 
 	// Parse the URL
-	url, err := url.Parse(req.URL)
+	url, err := parseUploadURL(req.URL)
 	if err != nil {
-		log.Error("url.Parse", err)
+		log.Error("parseUploadURL", err)
 		return nil, err
 	}
 
-	// init new block blob connection
 	token := cryp.HashUUID()
 	blobName := path.Join("public", token+path.Ext(path.Base(url.Path)))
 
-	if err := s.uploadURL(ctx, blobName, url); err != nil {
+	info, err := s.uploadURL(ctx, blobName, url, req.SessionId)
+	if err != nil {
 		return nil, err
 	}
 
@@ -58,7 +68,44 @@ func (s *service) UploadPublicURL(ctx context.Context, req *models.UploadURLRequ
 		Token:    token,
 		FileName: blobName,
 		Ext:      path.Ext(url.Path),
-		Url:      path.Join(s.cfg.AzBlob.Host, s.cfg.Storage.Container, blobName),
+		Url:      s.backend.GetURL(blobName),
+		Sha256:   info.sha256,
+		FileSize: info.size,
+		MimeType: info.mimeType,
+	}, nil
+}
+
+// Upload to private Blob Storage from url, copied by the backend or fetched through medioa
+func (s *service) UploadPrivateURL(ctx context.Context, req *models.UploadURLRequest) (*models.UploadResponse, error) {
+	log := log.New("service", "UploadPrivateURL")
+
+	if req.SecretId == "" {
+		return nil, fmt.Errorf("missing secret id before upload private url")
+	}
+
+	// Parse the URL
+	url, err := parseUploadURL(req.URL)
+	if err != nil {
+		log.Error("parseUploadURL", err)
+		return nil, err
+	}
+
+	token := cryp.HashUUID()
+	blobName := path.Join("private", req.SecretId, token+path.Ext(path.Base(url.Path)))
+
+	info, err := s.uploadURL(ctx, blobName, url, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UploadResponse{
+		Token:    token,
+		FileName: blobName,
+		Ext:      path.Ext(url.Path),
+		Url:      s.backend.GetURL(blobName),
+		Sha256:   info.sha256,
+		FileSize: info.size,
+		MimeType: info.mimeType,
 	}, nil
 }
 
@@ -67,7 +114,6 @@ func (s *service) UploadPublicURL(ctx context.Context, req *models.UploadURLRequ
 func (s *service) UploadPublicBlob(ctx context.Context, req *models.UploadBlobRequest) (*models.UploadResponse, error) {
 	log := log.New("service", "UploadPublicBlob")
 
-	// init new block blob connection
 	token := cryp.HashUUID()
 	blobName := path.Join("public", token+path.Ext(req.File.Filename))
 
@@ -82,7 +128,8 @@ func (s *service) UploadPublicBlob(ctx context.Context, req *models.UploadBlobRe
 		}
 	}
 
-	if err := s.uploadBlob(ctx, blobName, req.File, pr); err != nil {
+	checksum, err := s.uploadBlob(ctx, blobName, req.File, pr)
+	if err != nil {
 		return nil, err
 	}
 
@@ -90,7 +137,8 @@ func (s *service) UploadPublicBlob(ctx context.Context, req *models.UploadBlobRe
 		Token:    token,
 		FileName: blobName,
 		Ext:      path.Ext(req.File.Filename),
-		Url:      path.Join(s.cfg.AzBlob.Host, s.cfg.Storage.Container, blobName),
+		Url:      s.backend.GetURL(blobName),
+		Sha256:   checksum,
 	}, nil
 }
 
@@ -103,7 +151,6 @@ func (s *service) UploadPrivateBlob(ctx context.Context, req *models.UploadBlobR
 		return nil, fmt.Errorf("missing secret id before upload private blob")
 	}
 
-	// init new block blob connection
 	token := cryp.HashUUID()
 	blobName := path.Join("private", req.SecretId, token+path.Ext(req.File.Filename))
 
@@ -119,7 +166,8 @@ func (s *service) UploadPrivateBlob(ctx context.Context, req *models.UploadBlobR
 	}
 
 	// upload blob
-	if err := s.uploadBlob(ctx, blobName, req.File, pr); err != nil {
+	checksum, err := s.uploadBlob(ctx, blobName, req.File, pr)
+	if err != nil {
 		return nil, err
 	}
 
@@ -127,7 +175,59 @@ func (s *service) UploadPrivateBlob(ctx context.Context, req *models.UploadBlobR
 		Token:    token,
 		FileName: blobName,
 		Ext:      path.Ext(req.File.Filename),
-		Url:      path.Join(s.cfg.AzBlob.Host, s.cfg.Storage.Container, blobName),
+		Url:      s.backend.GetURL(blobName),
+		Sha256:   checksum,
+	}, nil
+}
+
+// Upload a stream (public/private) to Blob Storage as it is received, nothing is spooled to disk
+// and memory is bounded by the backend block size times the streams relayed at once
+func (s *service) UploadStream(ctx context.Context, req *models.UploadStreamRequest) (*models.UploadResponse, error) {
+	log := log.New("service", "UploadStream")
+
+	select {
+	case s.streams <- struct{}{}:
+		defer func() { <-s.streams }()
+	default:
+		return nil, fmt.Errorf("too many concurrent streams (max: %d), retry later", s.cfg.Upload.StreamMax)
+	}
+
+	token := cryp.HashUUID()
+	ext := path.Ext(req.FileName)
+	blobName := path.Join("public", token+ext)
+	if req.SecretId != "" {
+		blobName = path.Join("private", req.SecretId, token+ext)
+	}
+
+	// report the progress over the websocket, it is unknown without a content length
+	totalBytes := req.Size
+	pr := func(bytesTransferred int64) {
+		if totalBytes <= 0 {
+			return
+		}
+		percentage := float64(bytesTransferred) / float64(totalBytes) * 100
+		ws := s.lib.SocketConn.Get(req.SessionId)
+		if ws != nil {
+			_ = ws.Write([]byte(fmt.Sprintf("%f", percentage)))
+		}
+	}
+	reader := streaming.NewResponseProgress(io.NopCloser(req.Reader), pr)
+
+	// compute checksum and size while uploading
+	hash := sha256.New()
+	counter := &countWriter{}
+	if err := s.backend.UploadStream(ctx, blobName, io.TeeReader(reader, io.MultiWriter(hash, counter))); err != nil {
+		log.Error("backend.UploadStream", err)
+		return nil, err
+	}
+
+	return &models.UploadResponse{
+		Token:    token,
+		FileName: blobName,
+		Ext:      ext,
+		Url:      s.backend.GetURL(blobName),
+		Sha256:   hex.EncodeToString(hash.Sum(nil)),
+		FileSize: counter.n,
 	}, nil
 }
 
@@ -136,13 +236,30 @@ func (s *service) UploadPrivateBlob(ctx context.Context, req *models.UploadBlobR
 func (s *service) UploadPublicChunk(ctx context.Context, req *models.UploadChunkRequest) (*models.UploadChunkResponse, error) {
 	log := log.New("service", "UploadPublicChunk")
 
-	// init new block blob connection
 	token := req.Token
 	if token == "" {
 		token = cryp.HashUUID()
 	}
 	blobName := path.Join("public", token+path.Ext(req.FileName))
 
+	// only the last chunk may be smaller than the backend min block size
+	if req.ChunkIndex < req.TotalChunks-1 {
+		if err := s.checkBlockSize(req.Chunk.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	// a new blob starts its staged upload, the caller keeps the id for the next chunks
+	uploadId := req.UploadId
+	if req.Token == "" {
+		id, err := s.backend.CreateUpload(ctx, blobName)
+		if err != nil {
+			log.Error("backend.CreateUpload", err)
+			return nil, err
+		}
+		uploadId = id
+	}
+
 	// open file
 	reader, err := req.Chunk.Open()
 	if err != nil {
@@ -151,12 +268,19 @@ func (s *service) UploadPublicChunk(ctx context.Context, req *models.UploadChunk
 	}
 	defer reader.Close()
 
+	// fold the chunk into the running checksum
+	var hashState []byte
+	if req.Hash {
+		if hashState, err = hashBlock(req.HashState, reader); err != nil {
+			log.Error("hashBlock", err)
+			return nil, err
+		}
+	}
+
 	// stage block
 	blockId := blockIdBase64(req.ChunkIndex)
-	opts := &blockblob.StageBlockOptions{}
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.StageBlock(ctx, blockId, reader, opts); err != nil {
-		log.Error("blobClient.StageBlock", err)
+	if err := s.backend.StageBlock(ctx, blobName, uploadId, blockId, reader); err != nil {
+		log.Error("backend.StageBlock", err)
 		return nil, err
 	}
 
@@ -167,16 +291,17 @@ func (s *service) UploadPublicChunk(ctx context.Context, req *models.UploadChunk
 		if percentage > 100 {
 			percentage = 100
 		}
-		fmt.Println(percentage)
 		_ = ws.Write([]byte(fmt.Sprintf("%f", percentage)))
 	}
 
 	return &models.UploadChunkResponse{
-		Token:    token,
-		BlockId:  blockId,
-		FileName: blobName,
-		Ext:      path.Ext(req.FileName),
-		Url:      path.Join(s.cfg.AzBlob.Host, s.cfg.Storage.Container, blobName),
+		Token:     token,
+		UploadId:  uploadId,
+		BlockId:   blockId,
+		FileName:  blobName,
+		Ext:       path.Ext(req.FileName),
+		Url:       s.backend.GetURL(blobName),
+		HashState: hashState,
 	}, nil
 }
 
@@ -194,31 +319,21 @@ func (s *service) CommitPublicChunk(ctx context.Context, req *models.CommitChunk
 		return nil, fmt.Errorf("missing block ids before commit chunk")
 	}
 
-	// init new block blob connection
-	opts := &blockblob.CommitBlockListOptions{}
 	blobName := path.Join("public", req.Token+path.Ext(req.FileName))
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.CommitBlockList(ctx, req.BlockIds, opts); err != nil {
-		log.Error("blobClient.CommitBlockList", err)
+	res, err := s.backend.CommitBlockList(ctx, blobName, req.UploadId, req.BlockIds)
+	if err != nil {
+		log.Error("backend.CommitBlockList", err)
 		return nil, err
 	}
 
-	var fileSize int64
-	var totalBlock int64
-	getBlock, err := blobClient.GetBlockList(ctx, blockblob.BlockListTypeCommitted, nil)
+	// whole file checksum of the committed blob, resumed after the hashed blocks
+	res.FileName = blobName
+	res.Sha256, err = s.checksumBlob(ctx, blobName, req.HashState, req.HashedSize, res.FileSize)
 	if err != nil {
-		log.Error("blobClient.GetBlockList", err)
 		return nil, err
 	}
-	for _, block := range getBlock.BlockList.CommittedBlocks {
-		fileSize += *block.Size
-		totalBlock++
-	}
 
-	return &models.CommitChunkRsponse{
-		FileSize:   fileSize,
-		TotalBlock: totalBlock,
-	}, nil
+	return res, nil
 }
 
 // Upload to private Blob Storage by chunk
@@ -230,13 +345,30 @@ func (s *service) UploadPrivateChunk(ctx context.Context, req *models.UploadChun
 		return nil, fmt.Errorf("missing secret id before upload private chunk")
 	}
 
-	// init new block blob connection
 	token := req.Token
 	if token == "" {
 		token = cryp.HashUUID()
 	}
 	blobName := path.Join("private", req.SecretId, token+path.Ext(req.FileName))
 
+	// only the last chunk may be smaller than the backend min block size
+	if req.ChunkIndex < req.TotalChunks-1 {
+		if err := s.checkBlockSize(req.Chunk.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	// a new blob starts its staged upload, the caller keeps the id for the next chunks
+	uploadId := req.UploadId
+	if req.Token == "" {
+		id, err := s.backend.CreateUpload(ctx, blobName)
+		if err != nil {
+			log.Error("backend.CreateUpload", err)
+			return nil, err
+		}
+		uploadId = id
+	}
+
 	// open file
 	reader, err := req.Chunk.Open()
 	if err != nil {
@@ -245,12 +377,19 @@ func (s *service) UploadPrivateChunk(ctx context.Context, req *models.UploadChun
 	}
 	defer reader.Close()
 
+	// fold the chunk into the running checksum
+	var hashState []byte
+	if req.Hash {
+		if hashState, err = hashBlock(req.HashState, reader); err != nil {
+			log.Error("hashBlock", err)
+			return nil, err
+		}
+	}
+
 	// stage block
 	blockId := blockIdBase64(req.ChunkIndex)
-	opts := &blockblob.StageBlockOptions{}
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.StageBlock(ctx, blockId, reader, opts); err != nil {
-		log.Error("blobClient.StageBlock", err)
+	if err := s.backend.StageBlock(ctx, blobName, uploadId, blockId, reader); err != nil {
+		log.Error("backend.StageBlock", err)
 		return nil, err
 	}
 
@@ -261,16 +400,17 @@ func (s *service) UploadPrivateChunk(ctx context.Context, req *models.UploadChun
 		if percentage > 100 {
 			percentage = 100
 		}
-		fmt.Println(percentage)
 		_ = ws.Write([]byte(fmt.Sprintf("%f", percentage)))
 	}
 
 	return &models.UploadChunkResponse{
-		Token:    token,
-		BlockId:  blockId,
-		FileName: blobName,
-		Ext:      path.Ext(req.FileName),
-		Url:      path.Join(s.cfg.AzBlob.Host, s.cfg.Storage.Container, blobName),
+		Token:     token,
+		UploadId:  uploadId,
+		BlockId:   blockId,
+		FileName:  blobName,
+		Ext:       path.Ext(req.FileName),
+		Url:       s.backend.GetURL(blobName),
+		HashState: hashState,
 	}, nil
 }
 
@@ -291,104 +431,400 @@ func (s *service) CommitPrivateChunk(ctx context.Context, req *models.CommitChun
 		return nil, fmt.Errorf("missing block ids before commit chunk")
 	}
 
-	// init new block blob connection
-	opts := &blockblob.CommitBlockListOptions{}
 	blobName := path.Join("private", req.SecretId, req.Token+path.Ext(req.FileName))
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.CommitBlockList(ctx, req.BlockIds, opts); err != nil {
-		log.Error("blobClient.CommitBlockList", err)
+	res, err := s.backend.CommitBlockList(ctx, blobName, req.UploadId, req.BlockIds)
+	if err != nil {
+		log.Error("backend.CommitBlockList", err)
 		return nil, err
 	}
 
-	var fileSize int64
-	var totalBlock int64
-	getBlock, err := blobClient.GetBlockList(ctx, blockblob.BlockListTypeCommitted, nil)
+	// whole file checksum of the committed blob, resumed after the hashed blocks
+	res.FileName = blobName
+	res.Sha256, err = s.checksumBlob(ctx, blobName, req.HashState, req.HashedSize, res.FileSize)
 	if err != nil {
-		log.Error("blobClient.GetBlockList", err)
 		return nil, err
 	}
-	for _, block := range getBlock.BlockList.CommittedBlocks {
-		fileSize += *block.Size
-		totalBlock++
+
+	return res, nil
+}
+
+// Start the staged upload of a public/private blob (resumable upload)
+func (s *service) CreateUpload(ctx context.Context, req *models.CreateUploadRequest) (*models.CreateUploadResponse, error) {
+	log := log.New("service", "CreateUpload")
+
+	if req.Token == "" {
+		return nil, fmt.Errorf("missing token before create upload")
+	}
+
+	uploadId, err := s.backend.CreateUpload(ctx, stagedBlobName(req.SecretId, req.Token, req.Ext))
+	if err != nil {
+		log.Error("backend.CreateUpload", err)
+		return nil, err
 	}
 
-	return &models.CommitChunkRsponse{
-		FileSize:   fileSize,
-		TotalBlock: totalBlock,
+	return &models.CreateUploadResponse{
+		UploadId: uploadId,
 	}, nil
 }
 
-// Download from Blob Storage with SAS (Shared Access Signature)
-// https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/storage/azblob/sas/examples_test.go
-func (s *service) DownloadSAS(ctx context.Context, req *models.DownloadSASRequest) (*models.DownloadSASResponse, error) {
-	log := log.New("service", "DownloadSAS")
-	host := s.cfg.AzBlob.Host
-	containerName := s.cfg.Storage.Container
+// Stage a block of public/private blob from a reader (resumable upload)
+func (s *service) StageBlock(ctx context.Context, req *models.StageBlockRequest) (*models.UploadChunkResponse, error) {
+	log := log.New("service", "StageBlock")
 
-	blobURL := fmt.Sprintf("%s/%s/%s", host, containerName, req.FileName)
-	blobCli, err := blob.NewClientWithSharedKeyCredential(blobURL, s.lib.Blob.Credential, nil)
-	if err != nil {
-		log.Error("blockblob.NewClientWithSharedKeyCredential", err)
+	if req.Token == "" {
+		return nil, fmt.Errorf("missing token before stage block")
+	}
+
+	if !req.Final {
+		size, err := req.Reader.Seek(0, io.SeekEnd)
+		if err != nil {
+			log.Error("Reader.Seek", err)
+			return nil, err
+		}
+		if _, err := req.Reader.Seek(0, io.SeekStart); err != nil {
+			log.Error("Reader.Seek", err)
+			return nil, err
+		}
+		if err := s.checkBlockSize(size); err != nil {
+			return nil, err
+		}
+	}
+
+	// fold the block into the running checksum
+	var hashState []byte
+	if req.Hash {
+		var err error
+		if hashState, err = hashBlock(req.HashState, req.Reader); err != nil {
+			log.Error("hashBlock", err)
+			return nil, err
+		}
+	}
+
+	blobName := stagedBlobName(req.SecretId, req.Token, req.Ext)
+	blockId := blockIdBase64(req.ChunkIndex)
+	if err := s.backend.StageBlock(ctx, blobName, req.UploadId, blockId, req.Reader); err != nil {
+		log.Error("backend.StageBlock", err)
 		return nil, err
 	}
 
+	return &models.UploadChunkResponse{
+		Token:     req.Token,
+		UploadId:  req.UploadId,
+		BlockId:   blockId,
+		FileName:  blobName,
+		Ext:       req.Ext,
+		Url:       s.backend.GetURL(blobName),
+		HashState: hashState,
+	}, nil
+}
+
+// Download from Blob Storage with SAS (Shared Access Signature)
+func (s *service) DownloadSAS(ctx context.Context, req *models.DownloadSASRequest) (*models.DownloadSASResponse, error) {
+	log := log.New("service", "DownloadSAS")
+
 	now := time.Now().Add(-10 * time.Second)
 	expiry := now.Add(time.Duration(s.cfg.Download.Expire) * time.Minute)
-	permissions := sas.BlobPermissions{Read: true}
 
-	sasURL, err := blobCli.GetSASURL(permissions, expiry, nil)
+	sasURL, err := s.backend.GetSASURL(ctx, req.FileName, expiry)
 	if err != nil {
-		log.Error("blobCli.GetSASURL", err)
+		log.Error("backend.GetSASURL", err)
 		return nil, err
 	}
-	fmt.Println(sasURL)
 
 	return &models.DownloadSASResponse{
 		Url: sasURL,
 	}, nil
 }
 
-func (s *service) uploadURL(ctx context.Context, blobName string, url *url.URL) error {
-	log := log.New("service", "uploadURL")
+// Reserve a blob name (public/private) and sign a short-lived write-only url for it,
+// the client uploads directly to the backend without going through medioa
+func (s *service) UploadSAS(ctx context.Context, req *models.UploadSASRequest) (*models.UploadSASResponse, error) {
+	log := log.New("service", "UploadSAS")
 
-	opts := &blockblob.UploadBlobFromURLOptions{
-		// Metadata: map[string]*string{},
+	token := cryp.HashUUID()
+	ext := path.Ext(req.FileName)
+	blobName := path.Join("public", token+ext)
+	if req.SecretId != "" {
+		blobName = path.Join("private", req.SecretId, token+ext)
 	}
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.UploadBlobFromURL(ctx, url.String(), opts); err != nil {
-		log.Error("blobClient.UploadBlobFromURL", err)
+
+	expiry := time.Now().Add(time.Duration(s.cfg.Upload.PresignExpire) * time.Minute)
+	res, err := s.backend.GetUploadURL(ctx, blobName, expiry)
+	if err != nil {
+		log.Error("backend.GetUploadURL", err)
+		return nil, err
+	}
+
+	res.Token = token
+	res.Ext = ext
+	res.FileName = blobName
+	res.ExpiredAt = expiry
+	return res, nil
+}
+
+// Inspect a stored blob (public/private), computes its size, sniffed mime type and checksum
+func (s *service) InspectBlob(ctx context.Context, req *models.InspectBlobRequest) (*models.InspectBlobResponse, error) {
+	if req.FileName == "" {
+		return nil, fmt.Errorf("missing file name before inspect blob")
+	}
+
+	info, err := s.inspectBlob(ctx, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InspectBlobResponse{
+		FileSize: info.size,
+		MimeType: info.mimeType,
+		Sha256:   info.sha256,
+	}, nil
+}
+
+// Read blob (public/private) content from Blob Storage, caller must close the reader
+func (s *service) ReadBlob(ctx context.Context, req *models.ReadBlobRequest) (io.ReadCloser, error) {
+	log := log.New("service", "ReadBlob")
+
+	if req.FileName == "" {
+		return nil, fmt.Errorf("missing file name before read blob")
+	}
+
+	reader, err := s.backend.Download(ctx, req.FileName)
+	if err != nil {
+		log.Error("backend.Download", err)
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// Delete blob (public/private) from Blob Storage
+func (s *service) DeleteBlob(ctx context.Context, req *models.DeleteBlobRequest) error {
+	log := log.New("service", "DeleteBlob")
+
+	if req.FileName == "" {
+		return fmt.Errorf("missing file name before delete blob")
+	}
+
+	if err := s.backend.Delete(ctx, req.FileName); err != nil {
+		log.Error("backend.Delete", err)
 		return err
 	}
 
 	return nil
 }
 
-func (s *service) uploadBlob(ctx context.Context, blobName string, file xtype.File, pr func(bytesTransferred int64)) error {
+func (s *service) uploadURL(ctx context.Context, blobName string, url *url.URL, sessionId string) (*blobInfo, error) {
+	log := log.New("service", "uploadURL")
+
+	var info *blobInfo
+	var err error
+	if s.cfg.Upload.URLServerFetch {
+		info, err = s.fetchURL(ctx, blobName, url, sessionId)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := s.backend.UploadURL(ctx, blobName, url.String()); err != nil {
+			log.Error("backend.UploadURL", err)
+			return nil, err
+		}
+
+		// blob is copied by the backend, read it back to inspect it
+		info, err = s.inspectBlob(ctx, blobName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a server-side copy (azure) has no size limit, drop oversized blobs afterwards
+	maxSize := s.cfg.Upload.MaxSizeMB << 20
+	if info.size > maxSize {
+		if err := s.backend.Delete(ctx, blobName); err != nil {
+			log.Error("backend.Delete", err)
+		}
+		return nil, fmt.Errorf("file size too large (max: %dMB)", s.cfg.Upload.MaxSizeMB)
+	}
+
+	return info, nil
+}
+
+// fetchURL downloads the url through medioa into the backend, refusing private addresses
+// and anything larger than the upload limit, progress is reported over the websocket session.
+func (s *service) fetchURL(ctx context.Context, blobName string, url *url.URL, sessionId string) (*blobInfo, error) {
+	log := log.New("service", "fetchURL")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		log.Error("http.NewRequestWithContext", err)
+		return nil, err
+	}
+	res, err := xhttp.NewSafeClient().Do(req)
+	if err != nil {
+		log.Error("client.Do", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	maxSize := s.cfg.Upload.MaxSizeMB << 20
+	if res.ContentLength > maxSize {
+		return nil, fmt.Errorf("file size too large (max: %dMB)", s.cfg.Upload.MaxSizeMB)
+	}
+
+	// report the progress over the websocket, it is unknown without a content length
+	totalBytes := res.ContentLength
+	pr := func(bytesTransferred int64) {
+		if totalBytes <= 0 {
+			return
+		}
+		percentage := float64(bytesTransferred) / float64(totalBytes) * 100
+		ws := s.lib.SocketConn.Get(sessionId)
+		if ws != nil {
+			_ = ws.Write([]byte(fmt.Sprintf("%f", percentage)))
+		}
+	}
+	body := http.MaxBytesReader(nil, streaming.NewResponseProgress(res.Body, pr), maxSize)
+
+	info, err := inspectStream(body, func(reader io.Reader) error {
+		return s.backend.UploadStream(ctx, blobName, reader)
+	})
+	if err != nil {
+		log.Error("backend.UploadStream", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			if err := s.backend.Delete(ctx, blobName); err != nil {
+				log.Error("backend.Delete", err)
+			}
+			return nil, fmt.Errorf("file size too large (max: %dMB)", s.cfg.Upload.MaxSizeMB)
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (s *service) uploadBlob(ctx context.Context, blobName string, file xtype.File, pr func(bytesTransferred int64)) (string, error) {
 	log := log.New("service", "uploadBlob")
 
 	// open file
 	reader, err := file.Open()
 	if err != nil {
 		log.Error("file.Open", err)
-		return err
+		return "", err
 	}
 	defer reader.Close()
 
 	// add progress reporting
 	reqProgress := streaming.NewRequestProgress(reader, pr)
 
-	opts := &blockblob.UploadStreamOptions{
-		// Metadata: map[string]*string{},
+	// compute checksum while uploading
+	hash := sha256.New()
+	if err := s.backend.UploadStream(ctx, blobName, io.TeeReader(reqProgress, hash)); err != nil {
+		log.Error("backend.UploadStream", err)
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checksumBlob computes the SHA-256 (hex) of a committed blob, the hash is resumed from the
+// state of its first hashedSize bytes so only the rest of the blob is read back
+func (s *service) checksumBlob(ctx context.Context, blobName string, state []byte, hashedSize, fileSize int64) (string, error) {
+	log := log.New("service", "checksumBlob")
+
+	h, err := resumeHash(state)
+	if err != nil {
+		log.Error("resumeHash", err)
+		return "", err
+	}
+	if len(state) == 0 || hashedSize > fileSize {
+		h, hashedSize = sha256.New(), 0
+	}
+	if hashedSize == fileSize {
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	reader, err := s.backend.DownloadRange(ctx, blobName, hashedSize)
+	if err != nil {
+		log.Error("backend.DownloadRange", err)
+		return "", err
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(h, reader); err != nil {
+		log.Error("io.Copy", err)
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// inspectBlob reads a stored blob back to compute its checksum, size and mime type
+func (s *service) inspectBlob(ctx context.Context, blobName string) (*blobInfo, error) {
+	log := log.New("service", "inspectBlob")
+
+	reader, err := s.backend.Download(ctx, blobName)
+	if err != nil {
+		log.Error("backend.Download", err)
+		return nil, err
 	}
-	blobClient := s.lib.Blob.Container.NewBlockBlobClient(blobName)
-	if _, err := blobClient.UploadStream(ctx, reqProgress, opts); err != nil {
-		log.Error("blobClient.UploadStream", err)
+	defer reader.Close()
+
+	info, err := inspectStream(reader, func(reader io.Reader) error {
+		_, err := io.Copy(io.Discard, reader)
 		return err
+	})
+	if err != nil {
+		log.Error("inspectStream", err)
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// resumeHash continues a sha256 from its marshaled state, an empty state starts a new one
+func resumeHash(state []byte) (hash.Hash, error) {
+	h := sha256.New()
+	if len(state) > 0 {
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			return nil, err
+		}
 	}
+	return h, nil
+}
+
+// hashBlock folds a block into a sha256 state, the block is rewound to be staged after
+func hashBlock(state []byte, reader io.ReadSeeker) ([]byte, error) {
+	h, err := resumeHash(state)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, reader); err != nil {
+		return nil, err
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return h.(encoding.BinaryMarshaler).MarshalBinary()
+}
 
+// checkBlockSize refuses a block the backend can't commit unless it is the last one
+func (s *service) checkBlockSize(size int64) error {
+	if minSize := s.backend.MinBlockSize(); size < minSize {
+		return fmt.Errorf("chunk size must be at least %dMB except for the last chunk", minSize>>20)
+	}
 	return nil
 }
 
+func stagedBlobName(secretId, token, ext string) string {
+	if secretId != "" {
+		return path.Join("private", secretId, token+ext)
+	}
+	return path.Join("public", token+ext)
+}
+
 func blockIdBase64(idx int64) string {
 	buf := make([]byte, binary.MaxVarintLen64)
 	binary.PutVarint(buf, idx)
@@ -0,0 +1,22 @@
+package init
+
+import (
+	"medioa/config"
+	"medioa/internal/lockout/service"
+	commonModel "medioa/models"
+)
+
+type Init struct {
+	Service service.IService
+}
+
+// NewInit shares the attempt store of the lib, counters must be seen by every handler group
+func NewInit(
+	cfg *config.Config,
+	lib *commonModel.Lib,
+) *Init {
+	service := service.InitService(cfg, lib.AttemptStore)
+	return &Init{
+		Service: service,
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	LOCKOUT_KIND_SECRET_LOGIN      = "secret_login"
+	LOCKOUT_KIND_SECRET_PIN        = "secret_pin"
+	LOCKOUT_KIND_DOWNLOAD_PASSWORD = "download_password"
+)
+
+const (
+	LOCKOUT_SCOPE_ACCOUNT = "account"
+	LOCKOUT_SCOPE_IP      = "ip"
+)
+
+// Attempt is the attempt counter of a subject, an attempt is counted before it is verified
+// and given back when it succeeds
+type Attempt struct {
+	Failures     int64
+	LastFailedAt time.Time
+	RetryAt      time.Time // backoff or lockout, no attempt before it
+}
+
+// Subject is what failed attempts are counted against, an account or a client ip
+type Subject struct {
+	Scope string
+	Id    string
+}
+
+func Account(id string) Subject {
+	return Subject{Scope: LOCKOUT_SCOPE_ACCOUNT, Id: id}
+}
+
+func IP(ip string) Subject {
+	return Subject{Scope: LOCKOUT_SCOPE_IP, Id: ip}
+}
+
+func (s Subject) Key(kind string) string {
+	return kind + ":" + s.Scope + ":" + s.Id
+}
+
+// LockedError is returned while a subject is backing off or locked out
+type LockedError struct {
+	RetryAt time.Time
+}
+
+func (e *LockedError) Error() string {
+	retryIn := time.Until(e.RetryAt).Round(time.Second)
+	if retryIn < time.Second {
+		retryIn = time.Second
+	}
+	return fmt.Sprintf("too many failed attempts, retry in %v", retryIn)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"medioa/internal/lockout/models"
+)
+
+type IService interface {
+	Reserve(ctx context.Context, kind string, subjects ...models.Subject) error
+	Release(ctx context.Context, kind string, subjects ...models.Subject) error
+	Reset(ctx context.Context, kind string, subjects ...models.Subject) error
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/internal/lockout/models"
+	"medioa/internal/lockout/store"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+)
+
+type service struct {
+	cfg   *config.Config
+	store store.IStore
+}
+
+func InitService(cfg *config.Config, store store.IStore) IService {
+	return &service{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// Reserve counts an attempt before it is verified, so parallel attempts can't all get in
+// before the first failure is recorded. It refuses while any subject is backing off or
+// locked out, and locks a subject out once it goes over its max attempts within the window.
+// Accounts back off exponentially on every attempt until one succeeds.
+func (s *service) Reserve(ctx context.Context, kind string, subjects ...models.Subject) error {
+	log := log.New("service", "Reserve")
+
+	cfg := s.cfg.Lockout
+	window := time.Duration(cfg.Window) * time.Minute
+	duration := time.Duration(cfg.Duration) * time.Minute
+	ttl := max(window, duration)
+	now := time.Now()
+
+	reserved := make([]models.Subject, 0, len(subjects))
+	failures := make([]int64, 0, len(subjects))
+	var retryAt time.Time
+	for _, subject := range subjects {
+		if subject.Id == "" {
+			continue
+		}
+		key := subject.Key(kind)
+		attempt, err := s.store.Incr(ctx, key, window, ttl)
+		if err != nil {
+			log.Error("service.store.Incr", err)
+			s.giveBack(ctx, kind, reserved)
+			return err
+		}
+
+		// blocked, the attempt was not counted
+		if attempt.RetryAt.After(now) {
+			retryAt = later(retryAt, attempt.RetryAt)
+			continue
+		}
+		reserved = append(reserved, subject)
+		failures = append(failures, attempt.Failures)
+
+		maxAttempts := cfg.MaxAttempts
+		if subject.Scope == models.LOCKOUT_SCOPE_IP {
+			maxAttempts = cfg.IPMaxAttempts
+		}
+		if attempt.Failures > maxAttempts {
+			lockedUntil := now.Add(duration)
+			if err := s.store.Block(ctx, key, lockedUntil, true, ttl); err != nil {
+				log.Error("service.store.Block", err)
+				s.giveBack(ctx, kind, reserved)
+				return err
+			}
+			// audit event
+			log.Info("audit: lockout triggered, kind: %s, scope: %s, subject: %s, failures: %d, locked until: %s",
+				kind, subject.Scope, subject.Id, attempt.Failures-1, lockedUntil.Format(time.RFC3339))
+			retryAt = later(retryAt, lockedUntil)
+		}
+	}
+
+	if !retryAt.IsZero() {
+		s.giveBack(ctx, kind, reserved)
+		return &models.LockedError{RetryAt: retryAt}
+	}
+
+	// an ip is shared by many callers, only accounts back off
+	for i, subject := range reserved {
+		if subject.Scope != models.LOCKOUT_SCOPE_ACCOUNT {
+			continue
+		}
+		if err := s.store.Block(ctx, subject.Key(kind), now.Add(s.backoff(failures[i])), false, ttl); err != nil {
+			log.Error("service.store.Block", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Release gives back a reserved attempt that succeeded, the account starts over
+// and the client ip keeps counting only its failures
+func (s *service) Release(ctx context.Context, kind string, subjects ...models.Subject) error {
+	log := log.New("service", "Release")
+
+	for _, subject := range subjects {
+		if subject.Id == "" {
+			continue
+		}
+		if subject.Scope == models.LOCKOUT_SCOPE_IP {
+			if err := s.store.Decr(ctx, subject.Key(kind)); err != nil {
+				log.Error("service.store.Decr", err)
+				return err
+			}
+			continue
+		}
+		if err := s.store.Delete(ctx, subject.Key(kind)); err != nil {
+			log.Error("service.store.Delete", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset forgets the attempts of subjects, e.g. after their credential was reset
+func (s *service) Reset(ctx context.Context, kind string, subjects ...models.Subject) error {
+	log := log.New("service", "Reset")
+
+	for _, subject := range subjects {
+		if subject.Id == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, subject.Key(kind)); err != nil {
+			log.Error("service.store.Delete", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// giveBack undoes the attempts counted by a refused reservation
+func (s *service) giveBack(ctx context.Context, kind string, subjects []models.Subject) {
+	log := log.New("service", "giveBack")
+
+	for _, subject := range subjects {
+		if err := s.store.Decr(ctx, subject.Key(kind)); err != nil {
+			log.Error("service.store.Decr", err)
+		}
+	}
+}
+
+// backoff doubles the base delay on every failure, up to the max delay
+func (s *service) backoff(failures int64) time.Duration {
+	baseDelay := time.Duration(s.cfg.Lockout.BaseDelay) * time.Second
+	maxDelay := time.Duration(s.cfg.Lockout.MaxDelay) * time.Second
+	shift := failures - 1
+	if shift >= 32 {
+		return maxDelay
+	}
+	return min(baseDelay<<shift, maxDelay)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
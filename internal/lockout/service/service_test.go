@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"medioa/config"
+	"medioa/internal/lockout/models"
+	"medioa/internal/lockout/store"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestService(cfg config.LockoutConfig) *service {
+	return &service{
+		cfg:   &config.Config{Lockout: cfg},
+		store: store.InitMemory(),
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s := newTestService(config.LockoutConfig{BaseDelay: 1, MaxDelay: 60})
+	tests := []struct {
+		name     string
+		failures int64
+		want     time.Duration
+	}{
+		{name: "first failure", failures: 1, want: time.Second},
+		{name: "second failure", failures: 2, want: 2 * time.Second},
+		{name: "third failure", failures: 3, want: 4 * time.Second},
+		{name: "last before max", failures: 6, want: 32 * time.Second},
+		{name: "capped at max", failures: 7, want: 60 * time.Second},
+		{name: "shift overflow", failures: 40, want: 60 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.backoff(tt.failures); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	type attempt struct {
+		subjects []models.Subject
+		release  bool // the attempt succeeds
+		locked   bool
+	}
+	account := models.Account("alice")
+	ip := models.IP("203.0.113.1")
+	tests := []struct {
+		name     string
+		cfg      config.LockoutConfig
+		attempts []attempt
+	}{
+		{
+			name: "account locked out over max attempts",
+			cfg:  config.LockoutConfig{MaxAttempts: 2, IPMaxAttempts: 100, Duration: 10, Window: 10},
+			attempts: []attempt{
+				{subjects: []models.Subject{account, ip}},
+				{subjects: []models.Subject{account, ip}},
+				{subjects: []models.Subject{account, ip}, locked: true},
+				{subjects: []models.Subject{account, ip}, locked: true},
+			},
+		},
+		{
+			name: "ip locked out across accounts",
+			cfg:  config.LockoutConfig{MaxAttempts: 100, IPMaxAttempts: 2, Duration: 10, Window: 10},
+			attempts: []attempt{
+				{subjects: []models.Subject{models.Account("a"), ip}},
+				{subjects: []models.Subject{models.Account("b"), ip}},
+				{subjects: []models.Subject{models.Account("c"), ip}, locked: true},
+				{subjects: []models.Subject{models.Account("d"), models.IP("203.0.113.2")}},
+			},
+		},
+		{
+			name: "succeeded attempts are not counted",
+			cfg:  config.LockoutConfig{MaxAttempts: 1, IPMaxAttempts: 1, Duration: 10, Window: 10},
+			attempts: []attempt{
+				{subjects: []models.Subject{account, ip}, release: true},
+				{subjects: []models.Subject{account, ip}, release: true},
+				{subjects: []models.Subject{account, ip}},
+				{subjects: []models.Subject{account, ip}, locked: true},
+			},
+		},
+		{
+			name: "account backs off after a failure",
+			cfg:  config.LockoutConfig{MaxAttempts: 100, IPMaxAttempts: 100, BaseDelay: 60, MaxDelay: 60, Duration: 10, Window: 10},
+			attempts: []attempt{
+				{subjects: []models.Subject{account, ip}},
+				{subjects: []models.Subject{account, ip}, locked: true},
+				{subjects: []models.Subject{models.Account("bob"), ip}},
+			},
+		},
+		{
+			name: "empty subjects are skipped",
+			cfg:  config.LockoutConfig{MaxAttempts: 1, IPMaxAttempts: 1, Duration: 10, Window: 10},
+			attempts: []attempt{
+				{subjects: []models.Subject{models.Account(""), models.IP("")}},
+				{subjects: []models.Subject{models.Account(""), models.IP("")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestService(tt.cfg)
+			for i, a := range tt.attempts {
+				err := s.Reserve(ctx, models.LOCKOUT_KIND_SECRET_LOGIN, a.subjects...)
+				var lockedErr *models.LockedError
+				if locked := errors.As(err, &lockedErr); locked != a.locked {
+					t.Fatalf("attempt %d: Reserve() error = %v, want locked %v", i, err, a.locked)
+				}
+				if err != nil && !a.locked {
+					t.Fatalf("attempt %d: Reserve() error = %v", i, err)
+				}
+				if a.release {
+					if err := s.Release(ctx, models.LOCKOUT_KIND_SECRET_LOGIN, a.subjects...); err != nil {
+						t.Fatalf("attempt %d: Release() error = %v", i, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReserveConcurrent(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int64
+		callers     int
+	}{
+		{name: "fewer callers than max attempts", maxAttempts: 10, callers: 5},
+		{name: "more callers than max attempts", maxAttempts: 5, callers: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestService(config.LockoutConfig{MaxAttempts: tt.maxAttempts, IPMaxAttempts: 1000, Duration: 10, Window: 10})
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			reserved := 0
+			for range tt.callers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := s.Reserve(ctx, models.LOCKOUT_KIND_SECRET_PIN, models.Account("alice")); err == nil {
+						mu.Lock()
+						reserved++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			if want := min(int64(tt.callers), tt.maxAttempts); int64(reserved) != want {
+				t.Errorf("reserved %d attempts, want %d", reserved, want)
+			}
+		})
+	}
+}
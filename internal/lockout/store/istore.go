@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+	"medioa/internal/lockout/models"
+	"time"
+)
+
+// IStore keeps attempt counters, every method is atomic on its key so parallel attempts
+// can't overwrite each other's counts. A shared store implements Incr and Decr with
+// INCR/DECR and Block with a script.
+type IStore interface {
+	Get(ctx context.Context, key string) (*models.Attempt, error)
+	// Incr counts an attempt and returns the counter after it. A blocked key is returned
+	// unchanged, counters older than the window start over and the key expires after ttl.
+	Incr(ctx context.Context, key string, window, ttl time.Duration) (*models.Attempt, error)
+	// Decr gives back an attempt counted by Incr
+	Decr(ctx context.Context, key string) error
+	// Block refuses attempts until the time, reset clears the counter so it starts over after it
+	Block(ctx context.Context, key string, until time.Time, reset bool, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
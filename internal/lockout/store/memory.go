@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"medioa/internal/lockout/models"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired entries are dropped on write
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	attempt   models.Attempt
+	expiredAt time.Time
+}
+
+// memory keeps counters in process, they are lost on restart and not shared between instances
+type memory struct {
+	sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+func InitMemory() IStore {
+	return &memory{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func (m *memory) Get(ctx context.Context, key string) (*models.Attempt, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	entry := m.get(key, time.Now())
+	if entry == nil {
+		return nil, nil
+	}
+	attempt := entry.attempt
+	return &attempt, nil
+}
+
+func (m *memory) Incr(ctx context.Context, key string, window, ttl time.Duration) (*models.Attempt, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	entry := m.get(key, now)
+	if entry == nil {
+		// sweep before adding, a new entry has no expiry yet
+		m.sweep(now)
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+	if entry.attempt.RetryAt.After(now) {
+		attempt := entry.attempt
+		return &attempt, nil
+	}
+	if now.Sub(entry.attempt.LastFailedAt) > window {
+		entry.attempt.Failures = 0
+	}
+	entry.attempt.Failures++
+	entry.attempt.LastFailedAt = now
+	entry.expiredAt = later(entry.expiredAt, now.Add(ttl))
+
+	attempt := entry.attempt
+	return &attempt, nil
+}
+
+func (m *memory) Decr(ctx context.Context, key string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	entry := m.get(key, time.Now())
+	if entry != nil && entry.attempt.Failures > 0 {
+		entry.attempt.Failures--
+	}
+	return nil
+}
+
+func (m *memory) Block(ctx context.Context, key string, until time.Time, reset bool, ttl time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	entry := m.get(key, now)
+	if entry == nil {
+		// sweep before adding, a new entry has no expiry yet
+		m.sweep(now)
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+	entry.attempt.RetryAt = later(entry.attempt.RetryAt, until)
+	if reset {
+		entry.attempt.Failures = 0
+	}
+	entry.expiredAt = later(entry.expiredAt, now.Add(ttl))
+	return nil
+}
+
+func (m *memory) Delete(ctx context.Context, key string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// get returns the live entry of key, the lock must be held
+func (m *memory) get(key string, now time.Time) *memoryEntry {
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	if !entry.expiredAt.After(now) {
+		delete(m.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// sweep drops expired entries at most once per interval, the lock must be held
+func (m *memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	for k, entry := range m.entries {
+		if !entry.expiredAt.After(now) {
+			delete(m.entries, k)
+		}
+	}
+	m.lastSweep = now
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,120 @@
+package backend
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"medioa/config"
+	"medioa/internal/scanner/models"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	clamdChunkSize  = 64 * 1024
+	clamdReplyOK    = "OK"
+	clamdReplyFound = "FOUND"
+)
+
+// clamd streams content to a ClamAV daemon with the INSTREAM command,
+// https://docs.clamav.net/manual/Usage/Scanning.html#clamd
+type clamd struct {
+	cfg *config.Config
+}
+
+func InitClamd(cfg *config.Config) IBackend {
+	return &clamd{
+		cfg: cfg,
+	}
+}
+
+func (c *clamd) Scan(ctx context.Context, reader io.Reader) (*models.ScanResult, error) {
+	network, address, err := parseClamdAddress(c.cfg.Scanner.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(c.cfg.Scanner.Timeout) * time.Second
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	// each chunk is prefixed with its length (4 bytes, big endian), a zero length ends the stream
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, err
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return parseClamdReply(reply)
+}
+
+// parseClamdAddress accepts tcp://host:port or unix:///path/to/clamd.sock
+func parseClamdAddress(address string) (string, string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", err
+	}
+	switch u.Scheme {
+	case "tcp":
+		return u.Scheme, u.Host, nil
+	case "unix":
+		return u.Scheme, u.Path, nil
+	default:
+		return "", "", fmt.Errorf("clamd address scheme is invalid: %s", u.Scheme)
+	}
+}
+
+// parseClamdReply parses "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+func parseClamdReply(reply string) (*models.ScanResult, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	status := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case status == clamdReplyOK:
+		return &models.ScanResult{}, nil
+	case strings.HasSuffix(status, " "+clamdReplyFound):
+		return &models.ScanResult{
+			Infected:  true,
+			Signature: strings.TrimSuffix(status, " "+clamdReplyFound),
+		}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"medioa/internal/scanner/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    *models.ScanResult
+		wantErr bool
+	}{
+		{name: "clean", reply: "stream: OK", want: &models.ScanResult{}},
+		{name: "clean with null terminator", reply: "stream: OK\x00", want: &models.ScanResult{}},
+		{name: "clean with newline", reply: "stream: OK\n", want: &models.ScanResult{}},
+		{
+			name:  "infected",
+			reply: "stream: Eicar-Signature FOUND\x00",
+			want:  &models.ScanResult{Infected: true, Signature: "Eicar-Signature"},
+		},
+		{
+			name:  "infected signature with spaces",
+			reply: "stream: Win.Test.EICAR_HDB-1 Heuristic FOUND",
+			want:  &models.ScanResult{Infected: true, Signature: "Win.Test.EICAR_HDB-1 Heuristic"},
+		},
+		{name: "size limit", reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: true},
+		{name: "stream error", reply: "stream: Can't allocate memory ERROR", wantErr: true},
+		{name: "found without signature", reply: "stream: FOUND", wantErr: true},
+		{name: "empty", reply: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseClamdReply(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseClamdReply(%q) error = %v, wantErr %v", tt.reply, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseClamdReply(%q) = %+v, want %+v", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
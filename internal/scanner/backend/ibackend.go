@@ -0,0 +1,11 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"medioa/internal/scanner/models"
+)
+
+type IBackend interface {
+	Scan(ctx context.Context, reader io.Reader) (*models.ScanResult, error)
+}
@@ -0,0 +1,24 @@
+package init
+
+import (
+	"medioa/config"
+	"medioa/internal/scanner/backend"
+)
+
+type Init struct {
+	Backend backend.IBackend
+}
+
+// NewInit returns an Init with a nil Backend when scanning is disabled
+func NewInit(
+	cfg *config.Config,
+) *Init {
+	var scanBackend backend.IBackend
+	switch cfg.Scanner.Backend {
+	case config.SCANNER_BACKEND_CLAMD:
+		scanBackend = backend.InitClamd(cfg)
+	}
+	return &Init{
+		Backend: scanBackend,
+	}
+}
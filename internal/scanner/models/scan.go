@@ -0,0 +1,6 @@
+package models
+
+type ScanResult struct {
+	Infected  bool
+	Signature string
+}
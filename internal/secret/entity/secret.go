@@ -69,6 +69,7 @@ func (e *Secret) ParseFromSaveRequest(req *models.SaveRequest) {
 func (e *Secret) ParseForCreate(req *models.SaveRequest, userId int64) {
 	e.ParseFromSaveRequest(req)
 	_ = e.hashPassword()
+	_ = e.hashPinCode()
 	e.CreatedBy = userId
 	e.CreatedAt = time.Now()
 }
@@ -85,6 +86,13 @@ func (e *Secret) ParseForCreateMany(reqs []*models.SaveRequest, userId int64) []
 
 func (e *Secret) ParseForUpdate(req *models.SaveRequest, userId int64) {
 	e.ParseFromSaveRequest(req)
+	// only fields being changed are hashed, empty ones are not written
+	if e.Password != "" {
+		_ = e.hashPassword()
+	}
+	if e.PinCode != "" {
+		_ = e.hashPinCode()
+	}
 }
 
 func (e *Secret) ParseForUpdateMany(reqs []*models.SaveRequest, userId int64) []*Secret {
@@ -140,3 +148,12 @@ func (s *Secret) hashPassword() error {
 	s.Password = string(hashedPassword)
 	return nil
 }
+
+func (s *Secret) hashPinCode() error {
+	hashedPinCode, err := bcrypt.GenerateFromPassword([]byte(s.PinCode), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	s.PinCode = string(hashedPinCode)
+	return nil
+}
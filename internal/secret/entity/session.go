@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"medioa/internal/secret/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Session is a login of a secret, it holds the hash of the current refresh token
+type Session struct {
+	UUID        string     `bson:"_id"`
+	SecretId    string     `bson:"secret_id"`
+	RefreshHash string     `bson:"refresh_hash"`
+	ExpiredAt   time.Time  `bson:"expired_at"`
+	RevokedAt   *time.Time `bson:"revoked_at"`
+	CreatedAt   time.Time  `bson:"created_at"`
+}
+
+func (Session) TableName() string {
+	return "secret_sessions"
+}
+
+func (e *Session) Export() *models.SessionResponse {
+	return &models.SessionResponse{
+		UUID:        e.UUID,
+		SecretId:    e.SecretId,
+		RefreshHash: e.RefreshHash,
+		ExpiredAt:   e.ExpiredAt,
+		RevokedAt:   e.RevokedAt,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func (e *Session) ExportList(objs []*Session) []*models.SessionResponse {
+	res := make([]*models.SessionResponse, 0)
+	for _, obj := range objs {
+		res = append(res, obj.Export())
+	}
+	return res
+}
+
+func (e *Session) ParseFromSaveRequest(req *models.SessionSaveRequest) {
+	if req != nil {
+		e.UUID = req.UUID
+		e.SecretId = req.SecretId
+		e.RefreshHash = req.RefreshHash
+		e.ExpiredAt = req.ExpiredAt
+		e.RevokedAt = req.RevokedAt
+		e.CreatedAt = req.CreatedAt
+	}
+}
+
+func (e *Session) ParseForCreate(req *models.SessionSaveRequest) {
+	e.ParseFromSaveRequest(req)
+	e.CreatedAt = time.Now()
+}
+
+func (e *Session) ParseForUpdate(req *models.SessionSaveRequest) {
+	e.ParseFromSaveRequest(req)
+}
+
+func (e *Session) ParseForUpdateMany(reqs []*models.SessionSaveRequest) []*Session {
+	objs := make([]*Session, 0)
+	for _, v := range reqs {
+		obj := &Session{}
+		obj.ParseForUpdate(v)
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+func (e *Session) ToBson() bson.D {
+	d := make(bson.D, 0)
+	if e.UUID != "" {
+		d = append(d, bson.E{Key: "_id", Value: e.UUID})
+	}
+	if e.SecretId != "" {
+		d = append(d, bson.E{Key: "secret_id", Value: e.SecretId})
+	}
+	if e.RefreshHash != "" {
+		d = append(d, bson.E{Key: "refresh_hash", Value: e.RefreshHash})
+	}
+	if !e.ExpiredAt.IsZero() {
+		d = append(d, bson.E{Key: "expired_at", Value: e.ExpiredAt.UnixMilli()})
+	}
+	if e.RevokedAt != nil && !e.RevokedAt.IsZero() {
+		d = append(d, bson.E{Key: "revoked_at", Value: e.RevokedAt.UnixMilli()})
+	}
+	if !e.CreatedAt.IsZero() {
+		d = append(d, bson.E{Key: "created_at", Value: e.CreatedAt.UnixMilli()})
+	}
+	return d
+}
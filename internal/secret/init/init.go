@@ -8,19 +8,25 @@ import (
 )
 
 type Init struct {
-	Repository repository.IRepository
-	Service    service.IService
+	Repository        repository.IRepository
+	Service           service.IService
+	SessionRepository repository.ISessionRepository
+	SessionService    service.ISessionService
 }
 
 func NewInit(
 	cfg *config.Config,
 	lib *commonModel.Lib,
 ) *Init {
+	sessionRepository := repository.InitSessionMongo(cfg, lib)
+	sessionService := service.InitSessionService(cfg, lib, sessionRepository)
 	// repository := repository.InitRepo(lib)
 	repository := repository.InitMongo(cfg, lib)
 	service := service.InitService(cfg, lib, repository)
 	return &Init{
-		Repository: repository,
-		Service:    service,
+		Repository:        repository,
+		Service:           service,
+		SessionRepository: sessionRepository,
+		SessionService:    sessionService,
 	}
 }
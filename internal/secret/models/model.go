@@ -20,6 +20,7 @@ type RequestParams struct {
 	PinCode     string
 	AccessToken string
 	Type        string
+	IsMaster    *bool
 	CreatedBy   int64
 }
 
@@ -37,6 +38,12 @@ func (r *RequestParams) ToMap() map[string]any {
 	if strings.ToLower(r.OrderBy) != constants.SORT_ORDER_ASC {
 		r.OrderBy = constants.SORT_ORDER_DESC
 	}
+	if r.Page <= 0 {
+		r.Page = constants.DEFAULT_PAGE
+	}
+	if r.Size <= 0 {
+		r.Size = constants.DEFAULT_SIZE
+	}
 	return map[string]any{
 		constants.FIELD_SECRET_ID:           r.Id,
 		constants.FIELD_SECRET_UUID:         r.UUID,
@@ -45,6 +52,7 @@ func (r *RequestParams) ToMap() map[string]any {
 		constants.FIELD_SECRET_PIN_CODE:     r.PinCode,
 		constants.FIELD_SECRET_ACCESS_TOKEN: r.AccessToken,
 		constants.FIELD_SECRET_TYPE:         r.Type,
+		constants.FIELD_SECRET_IS_MASTER:    r.IsMaster,
 		constants.FIELD_SECRET_CREATED_BY:   r.CreatedBy,
 		constants.FIELD_PAGE:                r.Page,
 		constants.FIELD_SIZE:                r.Size,
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"medioa/constants"
+	"strings"
+	"time"
+)
+
+type SessionRequestParams struct {
+	UUID      string
+	SecretId  string
+	IsRevoked *bool
+}
+
+func (r *SessionRequestParams) ToMap() map[string]any {
+	r.UUID = strings.TrimSpace(r.UUID)
+	r.SecretId = strings.TrimSpace(r.SecretId)
+	return map[string]any{
+		constants.FIELD_SESSION_UUID:       r.UUID,
+		constants.FIELD_SESSION_SECRET_ID:  r.SecretId,
+		constants.FIELD_SESSION_IS_REVOKED: r.IsRevoked,
+	}
+}
+
+type SessionResponse struct {
+	UUID        string
+	SecretId    string
+	RefreshHash string
+	ExpiredAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// IsActive reports whether the session can still be refreshed
+func (r *SessionResponse) IsActive() bool {
+	return r.RevokedAt == nil && r.ExpiredAt.After(time.Now())
+}
+
+type SessionSaveRequest struct {
+	UUID        string
+	SecretId    string
+	RefreshHash string
+	ExpiredAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// RotateSessionRequest replaces the refresh hash of a live session, only while it is still the old one
+type RotateSessionRequest struct {
+	UUID           string
+	OldRefreshHash string
+	RefreshHash    string
+	ExpiredAt      time.Time
+}
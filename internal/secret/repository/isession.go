@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"medioa/internal/secret/entity"
+	"medioa/internal/secret/models"
+)
+
+type ISessionRepository interface {
+	GetOne(ctx context.Context, queries map[string]any) (*entity.Session, error)
+	GetList(ctx context.Context, queries map[string]any) ([]*entity.Session, error)
+	Create(ctx context.Context, obj *entity.Session) (*entity.Session, error)
+	Update(ctx context.Context, obj *entity.Session) (*entity.Session, error)
+	UpdateMany(ctx context.Context, objs []*entity.Session) (int64, error)
+	Rotate(ctx context.Context, req *models.RotateSessionRequest) (int64, error)
+}
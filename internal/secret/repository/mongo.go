@@ -6,10 +6,12 @@ import (
 	"medioa/constants"
 	"medioa/internal/secret/entity"
 	commonModel "medioa/models"
+	"medioa/pkg/xmongo"
 
 	"github.com/vukyn/kuery/conv"
 	"go.mongodb.org/mongo-driver/bson"
 	mongoo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongo struct {
@@ -31,27 +33,11 @@ func (m *mongo) withCollection() *mongoo.Collection {
 }
 
 func (m *mongo) GetById(ctx context.Context, id int64) (*entity.Secret, error) {
-	return nil, nil
+	queries := map[string]any{constants.FIELD_SECRET_ID: id}
+	return m.GetOne(ctx, queries)
 }
 func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Secret, error) {
-	filter := make([]bson.E, 0)
-	uuid := conv.ReadInterface(queries, constants.FIELD_STORAGE_UUID, "")
-	username := conv.ReadInterface(queries, constants.FIELD_SECRET_USERNAME, "")
-	accessToken := conv.ReadInterface(queries, constants.FIELD_SECRET_ACCESS_TOKEN, "")
-	_type := conv.ReadInterface(queries, constants.FIELD_SECRET_TYPE, "")
-
-	if uuid != "" {
-		filter = append(filter, bson.E{Key: "_id", Value: uuid})
-	}
-	if username != "" {
-		filter = append(filter, bson.E{Key: "username", Value: username})
-	}
-	if accessToken != "" {
-		filter = append(filter, bson.E{Key: "access_token", Value: accessToken})
-	}
-	if _type != "" {
-		filter = append(filter, bson.E{Key: "type", Value: _type})
-	}
+	filter := m.filter(queries)
 
 	var obj entity.Secret
 	err := m.withCollection().FindOne(ctx, filter).Decode(&obj)
@@ -63,13 +49,42 @@ func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Sec
 	return &obj, nil
 }
 func (m *mongo) GetList(ctx context.Context, queries map[string]any) ([]*entity.Secret, error) {
-	return nil, nil
+	filter := m.filter(queries)
+	opts := options.Find().SetSort(m.sort(queries))
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.Secret{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
 }
 func (m *mongo) GetListPaging(ctx context.Context, queries map[string]any) ([]*entity.Secret, error) {
-	return nil, nil
+	filter := m.filter(queries)
+	page := conv.ReadInterface(queries, constants.FIELD_PAGE, constants.DEFAULT_PAGE)
+	size := conv.ReadInterface(queries, constants.FIELD_SIZE, constants.DEFAULT_SIZE)
+	opts := xmongo.Paging(page, size).SetSort(m.sort(queries))
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.Secret{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
 }
 func (m *mongo) Count(ctx context.Context, queries map[string]any) (int64, error) {
-	return 0, nil
+	filter := m.filter(queries)
+	count, err := m.withCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 func (m *mongo) Create(ctx context.Context, obj *entity.Secret) (*entity.Secret, error) {
 	_, err := m.withCollection().InsertOne(ctx, obj.ToBson())
@@ -79,7 +94,17 @@ func (m *mongo) Create(ctx context.Context, obj *entity.Secret) (*entity.Secret,
 	return obj, nil
 }
 func (m *mongo) CreateMany(ctx context.Context, objs []*entity.Secret) ([]*entity.Secret, error) {
-	return nil, nil
+	if len(objs) == 0 {
+		return objs, nil
+	}
+	docs := make([]any, 0, len(objs))
+	for _, obj := range objs {
+		docs = append(docs, obj.ToBson())
+	}
+	if _, err := m.withCollection().InsertMany(ctx, docs); err != nil {
+		return nil, err
+	}
+	return objs, nil
 }
 func (m *mongo) Update(ctx context.Context, obj *entity.Secret) (*entity.Secret, error) {
 	_, err := m.withCollection().UpdateOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}}, bson.D{{Key: "$set", Value: obj.ToBson()}})
@@ -89,5 +114,73 @@ func (m *mongo) Update(ctx context.Context, obj *entity.Secret) (*entity.Secret,
 	return obj, nil
 }
 func (m *mongo) UpdateMany(ctx context.Context, objs []*entity.Secret) (int64, error) {
-	return 0, nil
+	if len(objs) == 0 {
+		return 0, nil
+	}
+	writes := make([]mongoo.WriteModel, 0, len(objs))
+	for _, obj := range objs {
+		writes = append(writes, mongoo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: obj.UUID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: obj.ToBson()}}))
+	}
+	res, err := m.withCollection().BulkWrite(ctx, writes)
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+func (m *mongo) sort(queries map[string]any) bson.D {
+	sortMultiple := conv.ReadInterface(queries, constants.FIELD_SORT_MULTIPLE, "")
+	sortBy := conv.ReadInterface(queries, constants.FIELD_SORT_BY, "")
+	orderBy := conv.ReadInterface(queries, constants.FIELD_ORDER_BY, constants.DEFAULT_SORT_ORDER)
+	allowed := map[string]string{
+		constants.FIELD_SECRET_ID:         "id",
+		constants.FIELD_SECRET_UUID:       "_id",
+		constants.FIELD_SECRET_USERNAME:   "username",
+		constants.FIELD_SECRET_TYPE:       "type",
+		constants.FIELD_SECRET_IS_MASTER:  "is_master",
+		constants.FIELD_SECRET_CREATED_BY: "created_by",
+		constants.FIELD_SECRET_CREATED_AT: "created_at",
+	}
+	return xmongo.Sort(sortMultiple, sortBy, orderBy, allowed, bson.D{{Key: "created_at", Value: -1}})
+}
+
+func (m *mongo) filter(queries map[string]any) bson.D {
+	filter := make(bson.D, 0)
+	id := conv.ReadInterface(queries, constants.FIELD_SECRET_ID, int64(0))
+	uuid := conv.ReadInterface(queries, constants.FIELD_SECRET_UUID, "")
+	username := conv.ReadInterface(queries, constants.FIELD_SECRET_USERNAME, "")
+	accessToken := conv.ReadInterface(queries, constants.FIELD_SECRET_ACCESS_TOKEN, "")
+	_type := conv.ReadInterface(queries, constants.FIELD_SECRET_TYPE, "")
+	isMaster := conv.ReadInterface(queries, constants.FIELD_SECRET_IS_MASTER, (*bool)(nil))
+	createdBy := conv.ReadInterface(queries, constants.FIELD_SECRET_CREATED_BY, int64(0))
+
+	if id != 0 {
+		filter = append(filter, bson.E{Key: "id", Value: id})
+	}
+	if uuid != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: uuid})
+	}
+	if username != "" {
+		filter = append(filter, bson.E{Key: "username", Value: username})
+	}
+	if accessToken != "" {
+		filter = append(filter, bson.E{Key: "access_token", Value: accessToken})
+	}
+	if _type != "" {
+		filter = append(filter, bson.E{Key: "type", Value: _type})
+	}
+	if isMaster != nil {
+		if *isMaster {
+			filter = append(filter, bson.E{Key: "is_master", Value: true})
+		} else {
+			// is_master is only written when true
+			filter = append(filter, bson.E{Key: "is_master", Value: bson.D{{Key: "$ne", Value: true}}})
+		}
+	}
+	if createdBy != 0 {
+		filter = append(filter, bson.E{Key: "created_by", Value: createdBy})
+	}
+	return filter
 }
@@ -218,6 +218,7 @@ func (r *repo) filter(query *gorm.DB, queries map[string]any) *gorm.DB {
 	username := conv.ReadInterface(queries, constants.FIELD_SECRET_USERNAME, "")
 	password := conv.ReadInterface(queries, constants.FIELD_SECRET_PASSWORD, "")
 	pinCode := conv.ReadInterface(queries, constants.FIELD_SECRET_PIN_CODE, "")
+	isMaster := conv.ReadInterface(queries, constants.FIELD_SECRET_IS_MASTER, (*bool)(nil))
 	createdBy := conv.ReadInterface(queries, constants.FIELD_SECRET_CREATED_BY, int64(0))
 
 	if id != int64(0) {
@@ -235,6 +236,9 @@ func (r *repo) filter(query *gorm.DB, queries map[string]any) *gorm.DB {
 	if pinCode != "" {
 		query = query.Where(r.tableName+"."+constants.FIELD_SECRET_PIN_CODE+" = ? ", pinCode)
 	}
+	if isMaster != nil {
+		query = query.Where(r.tableName+"."+constants.FIELD_SECRET_IS_MASTER+" = ? ", *isMaster)
+	}
 	if createdBy != int64(0) {
 		query = query.Where(r.tableName+"."+constants.FIELD_SECRET_CREATED_BY+" = ? ", createdBy)
 	}
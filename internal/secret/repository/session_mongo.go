@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/constants"
+	"medioa/internal/secret/entity"
+	"medioa/internal/secret/models"
+	commonModel "medioa/models"
+
+	"github.com/vukyn/kuery/conv"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sessionMongo struct {
+	cfg       *config.Config
+	lib       *commonModel.Lib
+	tableName string
+}
+
+func InitSessionMongo(cfg *config.Config, lib *commonModel.Lib) ISessionRepository {
+	return &sessionMongo{
+		cfg:       cfg,
+		lib:       lib,
+		tableName: (&entity.Session{}).TableName(),
+	}
+}
+
+func (m *sessionMongo) withCollection() *mongoo.Collection {
+	return m.lib.Mongo.Database(m.cfg.Mongo.Database).Collection(m.tableName)
+}
+
+func (m *sessionMongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Session, error) {
+	filter := m.filter(queries)
+
+	var obj entity.Session
+	err := m.withCollection().FindOne(ctx, filter).Decode(&obj)
+	if err == mongoo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+func (m *sessionMongo) GetList(ctx context.Context, queries map[string]any) ([]*entity.Session, error) {
+	filter := m.filter(queries)
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.Session{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+func (m *sessionMongo) Create(ctx context.Context, obj *entity.Session) (*entity.Session, error) {
+	_, err := m.withCollection().InsertOne(ctx, obj.ToBson())
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+func (m *sessionMongo) Update(ctx context.Context, obj *entity.Session) (*entity.Session, error) {
+	_, err := m.withCollection().UpdateOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}}, bson.D{{Key: "$set", Value: obj.ToBson()}})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+func (m *sessionMongo) UpdateMany(ctx context.Context, objs []*entity.Session) (int64, error) {
+	if len(objs) == 0 {
+		return 0, nil
+	}
+	writes := make([]mongoo.WriteModel, 0, len(objs))
+	for _, obj := range objs {
+		writes = append(writes, mongoo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: obj.UUID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: obj.ToBson()}}))
+	}
+	res, err := m.withCollection().BulkWrite(ctx, writes)
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+// Rotate swaps the refresh hash, it matches nothing once another refresh used the old one
+func (m *sessionMongo) Rotate(ctx context.Context, req *models.RotateSessionRequest) (int64, error) {
+	res, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: req.UUID},
+		{Key: "refresh_hash", Value: req.OldRefreshHash},
+		{Key: "revoked_at", Value: nil},
+	}, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "refresh_hash", Value: req.RefreshHash},
+			{Key: "expired_at", Value: req.ExpiredAt.UnixMilli()},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+func (m *sessionMongo) filter(queries map[string]any) bson.D {
+	filter := make(bson.D, 0)
+	uuid := conv.ReadInterface(queries, constants.FIELD_SESSION_UUID, "")
+	secretId := conv.ReadInterface(queries, constants.FIELD_SESSION_SECRET_ID, "")
+	isRevoked := conv.ReadInterface(queries, constants.FIELD_SESSION_IS_REVOKED, (*bool)(nil))
+
+	if uuid != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: uuid})
+	}
+	if secretId != "" {
+		filter = append(filter, bson.E{Key: "secret_id", Value: secretId})
+	}
+	if isRevoked != nil {
+		if *isRevoked {
+			filter = append(filter, bson.E{Key: "revoked_at", Value: bson.D{{Key: "$ne", Value: nil}}})
+		} else {
+			// revoked_at is only written when revoked
+			filter = append(filter, bson.E{Key: "revoked_at", Value: nil})
+		}
+	}
+	return filter
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"medioa/internal/secret/models"
+)
+
+type ISessionService interface {
+	GetOne(ctx context.Context, params *models.SessionRequestParams) (*models.SessionResponse, error)
+	GetList(ctx context.Context, params *models.SessionRequestParams) ([]*models.SessionResponse, error)
+	Create(ctx context.Context, params *models.SessionSaveRequest) (*models.SessionResponse, error)
+	Update(ctx context.Context, params *models.SessionSaveRequest) (*models.SessionResponse, error)
+	UpdateMany(ctx context.Context, params []*models.SessionSaveRequest) (int64, error)
+	Rotate(ctx context.Context, params *models.RotateSessionRequest) (int64, error)
+}
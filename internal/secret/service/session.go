@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/internal/secret/entity"
+	"medioa/internal/secret/models"
+	repo "medioa/internal/secret/repository"
+	commonModel "medioa/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+type sessionService struct {
+	cfg  *config.Config
+	lib  *commonModel.Lib
+	repo repo.ISessionRepository
+}
+
+func InitSessionService(cfg *config.Config, lib *commonModel.Lib, repo repo.ISessionRepository) ISessionService {
+	return &sessionService{
+		cfg:  cfg,
+		lib:  lib,
+		repo: repo,
+	}
+}
+
+func (s *sessionService) GetOne(ctx context.Context, params *models.SessionRequestParams) (*models.SessionResponse, error) {
+	log := log.New("sessionService", "GetOne")
+	record, err := s.repo.GetOne(ctx, params.ToMap())
+	if err != nil {
+		log.Error("sessionService.repo.GetOne: %v", err)
+		return nil, err
+	}
+	if record == nil {
+		return nil, nil
+	}
+	return record.Export(), nil
+}
+
+func (s *sessionService) GetList(ctx context.Context, params *models.SessionRequestParams) ([]*models.SessionResponse, error) {
+	log := log.New("sessionService", "GetList")
+	records, err := s.repo.GetList(ctx, params.ToMap())
+	if err != nil {
+		log.Error("sessionService.repo.GetList: %v", err)
+		return nil, err
+	}
+	return (&entity.Session{}).ExportList(records), nil
+}
+
+func (s *sessionService) Create(ctx context.Context, params *models.SessionSaveRequest) (*models.SessionResponse, error) {
+	log := log.New("sessionService", "Create")
+	obj := &entity.Session{}
+	obj.ParseForCreate(params)
+	res, err := s.repo.Create(ctx, obj)
+	if err != nil {
+		log.Error("sessionService.repo.Create: %v", err)
+		return nil, err
+	}
+	return res.Export(), nil
+}
+
+func (s *sessionService) Update(ctx context.Context, params *models.SessionSaveRequest) (*models.SessionResponse, error) {
+	log := log.New("sessionService", "Update")
+	obj := &entity.Session{}
+	obj.ParseForUpdate(params)
+	res, err := s.repo.Update(ctx, obj)
+	if err != nil {
+		log.Error("sessionService.repo.Update: %v", err)
+		return nil, err
+	}
+	return res.Export(), nil
+}
+
+func (s *sessionService) UpdateMany(ctx context.Context, params []*models.SessionSaveRequest) (int64, error) {
+	log := log.New("sessionService", "UpdateMany")
+	objs := (&entity.Session{}).ParseForUpdateMany(params)
+	res, err := s.repo.UpdateMany(ctx, objs)
+	if err != nil {
+		log.Error("sessionService.repo.UpdateMany: %v", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *sessionService) Rotate(ctx context.Context, params *models.RotateSessionRequest) (int64, error) {
+	log := log.New("sessionService", "Rotate")
+	res, err := s.repo.Rotate(ctx, params)
+	if err != nil {
+		log.Error("sessionService.repo.Rotate: %v", err)
+		return 0, err
+	}
+	return res, nil
+}
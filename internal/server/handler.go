@@ -2,7 +2,12 @@ package server
 
 import (
 	"medioa/config"
+	"medioa/constants"
+	initAdmin "medioa/internal/admin/init"
+	initAuth "medioa/internal/auth/init"
 	initAzBlob "medioa/internal/azblob/init"
+	initLockout "medioa/internal/lockout/init"
+	initScanner "medioa/internal/scanner/init"
 	initSecret "medioa/internal/secret/init"
 	initShare "medioa/internal/share/init"
 	initStorage "medioa/internal/storage/init"
@@ -18,33 +23,53 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func (s *Server) initHandlerApi(group *gin.RouterGroup) {
+// initModules builds the dependency graph once, every route group and job shares it
+func (s *Server) initModules() {
 	// Init azblob
-	azBlob := initAzBlob.NewInit(s.cfg, s.lib)
+	s.azBlob = initAzBlob.NewInit(s.cfg, s.lib)
 
 	// Init secret
 	secret := initSecret.NewInit(s.cfg, s.lib)
 
-	// Init storage
-	storage := initStorage.NewInit(s.cfg, s.lib, secret, azBlob)
-	storage.Handler.MapRoutes(group)
-}
+	// Init scanner
+	scanner := initScanner.NewInit(s.cfg)
 
-func (s *Server) initHandlerShare(group *gin.RouterGroup) {
-	// Init azblob
-	azBlob := initAzBlob.NewInit(s.cfg, s.lib)
+	// Init auth
+	s.auth = initAuth.NewInit(s.cfg, s.lib)
 
-	// Init secret
-	secret := initSecret.NewInit(s.cfg, s.lib)
+	// Init lockout
+	lockout := initLockout.NewInit(s.cfg, s.lib)
 
 	// Init storage
-	storage := initStorage.NewInit(s.cfg, s.lib, secret, azBlob)
+	s.storage = initStorage.NewInit(s.cfg, s.lib, secret, s.azBlob, scanner, s.auth, lockout)
+}
+
+// initAuth identifies callers on every route registered on the group after it
+func (s *Server) initAuth(group *gin.RouterGroup) {
+	group.Use(s.auth.Middleware.Identify())
+	s.auth.Handler.MapRoutes(group)
+}
 
+func (s *Server) initHandlerApi(group *gin.RouterGroup) {
+	s.storage.Handler.MapRoutes(group)
+}
+
+func (s *Server) initHandlerShare(group *gin.RouterGroup) {
 	// Init share
-	share := initShare.NewInit(s.cfg, s.lib, storage)
+	share := initShare.NewInit(s.cfg, s.lib, s.storage, s.auth)
 	share.Handler.MapRoutes(group)
 }
 
+func (s *Server) initHandlerAdmin(group *gin.RouterGroup) {
+	// Init admin
+	admin := initAdmin.NewInit(s.cfg, s.lib, s.storage, s.auth)
+	admin.Handler.MapRoutes(group)
+}
+
+func (s *Server) initHandlerBlob(group *gin.RouterGroup) {
+	s.azBlob.Handler.MapRoutes(group)
+}
+
 func (s *Server) initHealthCheck(group *gin.RouterGroup) {
 	pingHandler := func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, map[string]any{
@@ -123,7 +148,18 @@ func (s *Server) initCORS() {
 	if len(s.cfg.Cors.AllowMethods) > 0 {
 		corsConfig.AllowMethods = s.cfg.Cors.AllowMethods
 	} else {
-		corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE"}
+		corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "HEAD", "DELETE"}
+	}
+
+	// tus clients read these from responses
+	corsConfig.ExposeHeaders = []string{
+		"Location",
+		constants.TUS_HEADER_RESUMABLE,
+		constants.TUS_HEADER_VERSION,
+		constants.TUS_HEADER_EXTENSION,
+		constants.TUS_HEADER_CHECKSUM_ALGORITHM,
+		constants.TUS_HEADER_UPLOAD_OFFSET,
+		constants.TUS_HEADER_UPLOAD_LENGTH,
 	}
 
 	router.Use(cors.New(corsConfig))
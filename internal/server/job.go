@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"medioa/pkg/recover"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+	"github.com/vukyn/kuery/routine"
+)
+
+func (s *Server) initJobs(ctx context.Context) {
+	// purge trash
+	s.runJob(ctx, "purgeTrash", time.Duration(s.cfg.Trash.PurgeInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := s.storage.Usecase.PurgeTrash(ctx)
+		return err
+	})
+
+	// sweep expired files
+	s.runJob(ctx, "sweepExpired", time.Duration(s.cfg.LifeTime.SweepInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := s.storage.Usecase.SweepExpired(ctx)
+		return err
+	})
+
+	// cleanup abandoned chunked uploads
+	s.runJob(ctx, "cleanupPendingUploads", time.Duration(s.cfg.Upload.CleanupInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := s.storage.Usecase.CleanupPendingUploads(ctx)
+		return err
+	})
+}
+
+// runJob runs fn every interval until the server is stopped.
+func (s *Server) runJob(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	log := log.New("server", name)
+
+	routine.Run(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					log.Error("job failed", err)
+				}
+			}
+		}
+	}, recover.RecoverPanic)
+	log.Info("started job %s every %v", name, interval)
+}
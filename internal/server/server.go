@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"io"
 	"medioa/config"
+	initAuth "medioa/internal/auth/init"
+	initAzBlob "medioa/internal/azblob/init"
+	lockoutStore "medioa/internal/lockout/store"
+	initStorage "medioa/internal/storage/init"
 	"medioa/models"
 
 	"github.com/vukyn/kuery/network"
@@ -17,6 +21,8 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/olahol/melody"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -27,6 +33,11 @@ type Server struct {
 	cfg    *config.Config
 	router *gin.Engine
 	socket *melody.Melody
+	done   chan struct{}
+
+	azBlob  *initAzBlob.Init
+	auth    *initAuth.Init
+	storage *initStorage.Init
 }
 
 func New(ctx context.Context, cfg *config.Config) *Server {
@@ -35,9 +46,19 @@ func New(ctx context.Context, cfg *config.Config) *Server {
 		panic(err)
 	}
 
-	blobCli, err := initBlob(ctx, cfg)
-	if err != nil {
-		panic(err)
+	var blobCli *models.Blob
+	var s3Cli *minio.Core
+	switch cfg.Storage.Backend {
+	case config.STORAGE_BACKEND_AZBLOB:
+		blobCli, err = initBlob(ctx, cfg)
+		if err != nil {
+			panic(err)
+		}
+	case config.STORAGE_BACKEND_S3:
+		s3Cli, err = initS3(ctx, cfg)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	if cfg.App.Environment == config.APP_ENVIRONMENT_PROD {
@@ -45,13 +66,18 @@ func New(ctx context.Context, cfg *config.Config) *Server {
 		gin.DefaultWriter = io.Discard
 	}
 
-	router := initGin()
+	router, err := initGin(cfg)
+	if err != nil {
+		panic(err)
+	}
 	socket := initSocket()
 
 	lib := &models.Lib{
-		Mongo:      mongoCli,
-		Blob:       blobCli,
-		SocketConn: models.NewSocketConn(),
+		Mongo:        mongoCli,
+		Blob:         blobCli,
+		S3:           s3Cli,
+		SocketConn:   models.NewSocketConn(),
+		AttemptStore: lockoutStore.InitMemory(),
 	}
 
 	return &Server{
@@ -59,6 +85,7 @@ func New(ctx context.Context, cfg *config.Config) *Server {
 		lib:    lib,
 		router: router,
 		socket: socket,
+		done:   make(chan struct{}),
 	}
 }
 
@@ -66,6 +93,7 @@ func (s *Server) Start(ctx context.Context) {
 	log := log.New("server", "Start")
 
 	r := s.router
+	s.initModules()
 	s.initCORS()
 	s.initSwagger()
 	s.initStaticFiles()
@@ -73,16 +101,28 @@ func (s *Server) Start(ctx context.Context) {
 	// api v1
 	v1 := r.Group("/api/v1")
 	s.initHealthCheck(v1)
+	s.initAuth(v1)
 	s.initHandlerApi(v1)
 
+	// api admin, master secret only
+	admin := v1.Group("/admin")
+	s.initHandlerAdmin(admin)
+
 	// api share
 	share := r.Group("/share")
 	s.initHealthCheck(share)
 	s.initHandlerShare(share)
 
+	// blob
+	blob := r.Group("/blob")
+	s.initHandlerBlob(blob)
+
 	// socket
 	s.initSocket()
 
+	// background jobs
+	s.initJobs(ctx)
+
 	port := ":" + s.cfg.App.Port
 	log.Info("started api successfully with port: %v", port)
 
@@ -97,6 +137,8 @@ func (s *Server) Stop(ctx context.Context) {
 	log := log.New("server", "Stop")
 
 	log.Info("stopping api")
+	close(s.done)
+
 	if err := s.lib.Mongo.Disconnect(ctx); err != nil {
 		log.Error("failed to disconnect mongo", err)
 	}
@@ -106,7 +148,7 @@ func (s *Server) Stop(ctx context.Context) {
 	}
 }
 
-func initGin() *gin.Engine {
+func initGin(cfg *config.Config) (*gin.Engine, error) {
 	log := log.New("server", "initGin")
 
 	r := gin.Default()
@@ -114,7 +156,14 @@ func initGin() *gin.Engine {
 	gin.DebugPrintRouteFunc = func(httpMethod, absolutePath, handlerName string, nuHandlers int) {
 		log.Debug("endpoint %v %v %v", httpMethod, absolutePath, handlerName)
 	}
-	return r
+
+	// the client ip counts failed attempts, forwarded headers are only read from trusted proxies
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Error("failed to set trusted proxies", err)
+		return nil, err
+	}
+	r.TrustedPlatform = cfg.App.TrustedPlatform
+	return r, nil
 }
 
 func initSocket() *melody.Melody {
@@ -177,3 +226,33 @@ func initBlob(_ context.Context, cfg *config.Config) (*models.Blob, error) {
 		Service:    service,
 	}, nil
 }
+
+func initS3(ctx context.Context, cfg *config.Config) (*minio.Core, error) {
+	log := log.New("server", "initS3")
+	bucketName := cfg.Storage.Container
+
+	client, err := minio.NewCore(cfg.S3.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3.AccessKey, cfg.S3.SecretKey, ""),
+		Secure: cfg.S3.UseSSL,
+		Region: cfg.S3.Region,
+	})
+	if err != nil {
+		log.Error("minio.NewCore", err)
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, bucketName)
+	if err != nil {
+		log.Error("client.BucketExists", err)
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{Region: cfg.S3.Region}); err != nil {
+			log.Error("client.MakeBucket", err)
+			return nil, err
+		}
+	}
+	log.Info("connected to s3 successfully")
+
+	return client, nil
+}
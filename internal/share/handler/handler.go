@@ -4,6 +4,9 @@ import (
 	"medioa/config"
 	"medioa/constants"
 
+	initAuth "medioa/internal/auth/init"
+	authMiddleware "medioa/internal/auth/middleware"
+	authModel "medioa/internal/auth/models"
 	initStorage "medioa/internal/storage/init"
 	storageModel "medioa/internal/storage/models"
 	storageUC "medioa/internal/storage/usecase"
@@ -21,18 +24,20 @@ type Handler struct {
 	cfg       *config.Config
 	lib       *commonModel.Lib
 	storageUC storageUC.IUsecase
+	auth      authMiddleware.IMiddleware
 }
 
-func InitHandler(cfg *config.Config, lib *commonModel.Lib, storage *initStorage.Init) IHandler {
+func InitHandler(cfg *config.Config, lib *commonModel.Lib, storage *initStorage.Init, auth *initAuth.Init) IHandler {
 	return Handler{
 		cfg:       cfg,
 		lib:       lib,
 		storageUC: storage.Usecase,
+		auth:      auth.Middleware,
 	}
 }
 
 func (h Handler) MapRoutes(group *gin.RouterGroup) {
-	group.GET(constants.SHARE_ENDPOINT_DOWNLOAD, ratelimiter.LimitPerSecond(constants.RATE_LIMIT_DOWNLOAD_PER_SECOND), h.Download)
+	group.GET(constants.SHARE_ENDPOINT_DOWNLOAD, ratelimiter.LimitPerSecond(constants.RATE_LIMIT_DOWNLOAD_PER_SECOND), h.auth.Identify(), h.auth.Authorize(constants.AUTH_SCOPE_DOWNLOAD), h.Download)
 }
 
 // Download godoc
@@ -48,7 +53,7 @@ func (h Handler) MapRoutes(group *gin.RouterGroup) {
 //	@Success		200		{object}	storageModel.DownloadResponse
 //	@Router			/share/download/{file_id} [get]
 func (h Handler) Download(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	fileId := ctx.Param("file_id")
 	token := ctx.Query("token")
 	res, err := h.storageUC.GetFileInfo(ctx, userId, &storageModel.GetFileInfoRequest{
@@ -2,6 +2,7 @@ package init
 
 import (
 	"medioa/config"
+	initAuth "medioa/internal/auth/init"
 	"medioa/internal/share/handler"
 	initStorage "medioa/internal/storage/init"
 	commonModel "medioa/models"
@@ -15,8 +16,9 @@ func NewInit(
 	cfg *config.Config,
 	lib *commonModel.Lib,
 	initSecret *initStorage.Init,
+	initAuth *initAuth.Init,
 ) *Init {
-	handler := handler.InitHandler(cfg, lib, initSecret)
+	handler := handler.InitHandler(cfg, lib, initSecret, initAuth)
 	return &Init{
 		Handler: handler,
 	}
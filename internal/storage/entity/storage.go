@@ -11,21 +11,37 @@ import (
 )
 
 type Storage struct {
-	Id               int64     `gorm:"primarykey;column:id" bson:"id"`
-	UUID             string    `gorm:"column:uuid" bson:"_id"`
-	DownloadUrl      string    `gorm:"column:download_url" bson:"download_url"`
-	DownloadPassword string    `gorm:"column:download_password" bson:"download_password"`
-	Type             string    `gorm:"column:type" bson:"type"`
-	Token            string    `gorm:"column:token;default:(-)" bson:"token"`
-	LifeTime         int64     `gorm:"column:life_time;default:(-)" bson:"life_time"`
-	FileName         string    `gorm:"column:file_name" bson:"file_name"`
-	FileSize         int64     `gorm:"column:file_size" bson:"file_size"`
-	Ext              string    `gorm:"column:ext" bson:"ext"`
-	SecretId         string    `gorm:"column:secret_id" bson:"secret_id"`
-	CreatedBy        int64     `gorm:"column:created_by" bson:"created_by"`
-	CreatedAt        time.Time `gorm:"autoCreateTime" bson:"created_at"`
-	ChunkIds         *[]string `gorm:"column:chunk_ids" bson:"chunk_ids"`
-	TotalChunks      int64     `gorm:"column:total_chunks" bson:"total_chunks"`
+	Id               int64      `gorm:"primarykey;column:id" bson:"id"`
+	UUID             string     `gorm:"column:uuid" bson:"_id"`
+	DownloadUrl      string     `gorm:"column:download_url" bson:"download_url"`
+	DownloadPassword string     `gorm:"column:download_password" bson:"download_password"`
+	Type             string     `gorm:"column:type" bson:"type"`
+	Token            string     `gorm:"column:token;default:(-)" bson:"token"`
+	LifeTime         int64      `gorm:"column:life_time;default:(-)" bson:"life_time"`
+	FileName         string     `gorm:"column:file_name" bson:"file_name"`
+	FileSize         int64      `gorm:"column:file_size" bson:"file_size"`
+	Ext              string     `gorm:"column:ext" bson:"ext"`
+	SecretId         string     `gorm:"column:secret_id" bson:"secret_id"`
+	CreatedBy        int64      `gorm:"column:created_by" bson:"created_by"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" bson:"created_at"`
+	ChunkIds         *[]string  `gorm:"column:chunk_ids" bson:"chunk_ids"`
+	TotalChunks      int64      `gorm:"column:total_chunks" bson:"total_chunks"`
+	DeletedAt        *time.Time `gorm:"column:deleted_at" bson:"deleted_at"`
+	ExpiredAt        time.Time  `gorm:"column:expired_at" bson:"expired_at"`
+	UploadOffset     int64      `gorm:"column:upload_offset" bson:"upload_offset"`
+	UploadLength     int64      `gorm:"column:upload_length" bson:"upload_length"`
+	UploadedSize     int64      `gorm:"column:uploaded_size" bson:"uploaded_size"`
+	Sha256           string     `gorm:"column:sha256" bson:"sha256"`
+	BlobName         string     `gorm:"column:blob_name" bson:"blob_name"`
+	UploadId         string     `gorm:"column:upload_id" bson:"upload_id"`
+	HashState        []byte     `gorm:"column:hash_state" bson:"hash_state"`
+	HashedSize       int64      `gorm:"column:hashed_size" bson:"hashed_size"`
+	HashedChunks     int64      `gorm:"column:hashed_chunks" bson:"hashed_chunks"`
+	ChunkWrites      int64      `gorm:"column:chunk_writes" bson:"chunk_writes"`
+	ScanStatus       string     `gorm:"column:scan_status" bson:"scan_status"`
+	IsPending        *bool      `gorm:"column:is_pending" bson:"is_pending"`
+	IsReleasing      *bool      `gorm:"column:is_releasing" bson:"is_releasing"`
+	PatchLockedUntil *time.Time `gorm:"column:patch_locked_until" bson:"patch_locked_until"`
 }
 
 func (s *Storage) TableName() string {
@@ -54,6 +70,21 @@ func (e *Storage) Export() *models.Response {
 		CreatedAt:        e.CreatedAt,
 		ChunkIds:         chunkIds,
 		TotalChunks:      e.TotalChunks,
+		DeletedAt:        e.DeletedAt,
+		ExpiredAt:        e.ExpiredAt,
+		UploadOffset:     e.UploadOffset,
+		UploadLength:     e.UploadLength,
+		UploadedSize:     e.UploadedSize,
+		Sha256:           e.Sha256,
+		BlobName:         e.BlobName,
+		UploadId:         e.UploadId,
+		HashState:        e.HashState,
+		HashedSize:       e.HashedSize,
+		HashedChunks:     e.HashedChunks,
+		ChunkWrites:      e.ChunkWrites,
+		ScanStatus:       e.ScanStatus,
+		IsPending:        e.IsPending != nil && *e.IsPending,
+		IsReleasing:      e.IsReleasing != nil && *e.IsReleasing,
 	}
 }
 
@@ -82,6 +113,19 @@ func (e *Storage) ParseFromSaveRequest(req *models.SaveRequest) {
 		e.CreatedAt = req.CreatedAt
 		e.ChunkIds = req.ChunkIds
 		e.TotalChunks = req.TotalChunks
+		e.DeletedAt = req.DeletedAt
+		e.ExpiredAt = req.ExpiredAt
+		e.UploadOffset = req.UploadOffset
+		e.UploadLength = req.UploadLength
+		e.UploadedSize = req.UploadedSize
+		e.Sha256 = req.Sha256
+		e.BlobName = req.BlobName
+		e.UploadId = req.UploadId
+		e.HashState = req.HashState
+		e.HashedSize = req.HashedSize
+		e.ScanStatus = req.ScanStatus
+		e.IsPending = req.IsPending
+		e.IsReleasing = req.IsReleasing
 	}
 }
 
@@ -166,5 +210,49 @@ func (e *Storage) ToBson() bson.D {
 	if e.TotalChunks > 0 {
 		d = append(d, bson.E{Key: "total_chunks", Value: e.TotalChunks})
 	}
+	if e.UploadOffset > 0 {
+		d = append(d, bson.E{Key: "upload_offset", Value: e.UploadOffset})
+	}
+	if e.UploadLength > 0 {
+		d = append(d, bson.E{Key: "upload_length", Value: e.UploadLength})
+	}
+	if e.UploadedSize > 0 {
+		d = append(d, bson.E{Key: "uploaded_size", Value: e.UploadedSize})
+	}
+	if e.Sha256 != "" {
+		d = append(d, bson.E{Key: "sha256", Value: e.Sha256})
+	}
+	if e.BlobName != "" {
+		d = append(d, bson.E{Key: "blob_name", Value: e.BlobName})
+	}
+	if e.UploadId != "" {
+		d = append(d, bson.E{Key: "upload_id", Value: e.UploadId})
+	}
+	if len(e.HashState) > 0 {
+		d = append(d, bson.E{Key: "hash_state", Value: e.HashState})
+	}
+	if e.HashedSize > 0 {
+		d = append(d, bson.E{Key: "hashed_size", Value: e.HashedSize})
+	}
+	if e.ScanStatus != "" {
+		d = append(d, bson.E{Key: "scan_status", Value: e.ScanStatus})
+	}
+	if e.IsPending != nil {
+		d = append(d, bson.E{Key: "is_pending", Value: *e.IsPending})
+	}
+	if e.IsReleasing != nil {
+		d = append(d, bson.E{Key: "is_releasing", Value: *e.IsReleasing})
+	}
+	if !e.ExpiredAt.IsZero() {
+		d = append(d, bson.E{Key: "expired_at", Value: e.ExpiredAt.UnixMilli()})
+	}
+	if e.DeletedAt != nil {
+		if !e.DeletedAt.IsZero() {
+			d = append(d, bson.E{Key: "deleted_at", Value: e.DeletedAt.UnixMilli()})
+		} else {
+			// zero time clears the deleted state (restore)
+			d = append(d, bson.E{Key: "deleted_at", Value: nil})
+		}
+	}
 	return d
 }
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"medioa/config"
 	"medioa/constants"
@@ -8,7 +11,10 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	authMiddleware "medioa/internal/auth/middleware"
+	authModel "medioa/internal/auth/models"
 	"medioa/internal/storage/models"
 	"medioa/internal/storage/usecase"
 	commonModel "medioa/models"
@@ -21,28 +27,56 @@ type Handler struct {
 	cfg     *config.Config
 	lib     *commonModel.Lib
 	usecase usecase.IUsecase
+	auth    authMiddleware.IMiddleware
 }
 
-func InitHandler(cfg *config.Config, lib *commonModel.Lib, usecase usecase.IUsecase) IHandler {
+func InitHandler(cfg *config.Config, lib *commonModel.Lib, usecase usecase.IUsecase, auth authMiddleware.IMiddleware) IHandler {
 	return Handler{
 		cfg:     cfg,
 		lib:     lib,
 		usecase: usecase,
+		auth:    auth,
 	}
 }
 
 func (h Handler) MapRoutes(group *gin.RouterGroup) {
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD, h.Upload)
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_STAGE, h.UploadChunk)
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_COMMIT, h.CommitChunk)
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_WITH_SECRET, h.UploadWithSecret)
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_STAGE_WITH_SECRET, h.UploadChunkWithSecret)
-	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_COMMIT_WITH_SECRET, h.CommitChunkWithSecret)
-	group.GET(constants.STORAGE_ENDPOINT_DOWNLOAD, h.Download)
-	group.GET(constants.STORAGE_ENDPOINT_REQUEST_DOWNLOAD, h.RequestDownload)
-	group.POST(constants.STORAGE_ENDPOINT_CREATE_SECRET, h.CreateSecret)
-	group.PUT(constants.STORAGE_ENDPOINT_RETRIEVE_SECRET, h.RetrieveSecret)
-	group.PUT(constants.STORAGE_ENDPOINT_RESET_PIN_CODE, h.ResetPinCode)
+	uploadPublic := h.auth.Authenticate(constants.AUTH_SCOPE_UPLOAD_PUBLIC)
+	uploadPrivate := h.auth.Authenticate(constants.AUTH_SCOPE_UPLOAD_PRIVATE)
+	download := h.auth.Authenticate(constants.AUTH_SCOPE_DOWNLOAD)
+	// download is also reached through share links without auth
+	shareDownload := h.auth.Authorize(constants.AUTH_SCOPE_DOWNLOAD)
+
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD, uploadPublic, h.Upload)
+	group.PUT(constants.STORAGE_ENDPOINT_UPLOAD_STREAM, uploadPublic, h.UploadStream)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_STAGE, uploadPublic, h.UploadChunk)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_COMMIT, uploadPublic, h.CommitChunk)
+	group.GET(constants.STORAGE_ENDPOINT_UPLOAD_STATUS, uploadPublic, h.UploadStatus)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_PRESIGN, uploadPublic, h.PresignUpload)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_FINALIZE, uploadPublic, h.FinalizeUpload)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_WITH_SECRET, uploadPrivate, h.UploadWithSecret)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_STAGE_WITH_SECRET, uploadPrivate, h.UploadChunkWithSecret)
+	group.POST(constants.STORAGE_ENDPOINT_UPLOAD_COMMIT_WITH_SECRET, uploadPrivate, h.CommitChunkWithSecret)
+	group.GET(constants.STORAGE_ENDPOINT_DOWNLOAD, shareDownload, h.Download)
+	group.GET(constants.STORAGE_ENDPOINT_REQUEST_DOWNLOAD, download, h.RequestDownload)
+	group.POST(constants.STORAGE_ENDPOINT_CREATE_SECRET, uploadPrivate, h.CreateSecret)
+	group.PUT(constants.STORAGE_ENDPOINT_RETRIEVE_SECRET, uploadPrivate, h.RetrieveSecret)
+	group.POST(constants.STORAGE_ENDPOINT_REFRESH_SECRET, uploadPrivate, h.RefreshSecret)
+	group.POST(constants.STORAGE_ENDPOINT_LOGOUT_SECRET, uploadPrivate, h.LogoutSecret)
+	group.PUT(constants.STORAGE_ENDPOINT_RESET_PIN_CODE, uploadPrivate, h.ResetPinCode)
+	group.GET(constants.STORAGE_ENDPOINT_LIST_SECRET_FILES, uploadPrivate, h.ListSecretFiles)
+	group.DELETE(constants.STORAGE_ENDPOINT_DELETE, uploadPublic, h.Delete)
+	group.DELETE(constants.STORAGE_ENDPOINT_DELETE_WITH_SECRET, uploadPrivate, h.DeleteWithSecret)
+	group.GET(constants.STORAGE_ENDPOINT_LIST_SECRET_TRASH, uploadPrivate, h.ListSecretTrash)
+	group.POST(constants.STORAGE_ENDPOINT_RESTORE_WITH_SECRET, uploadPrivate, h.RestoreWithSecret)
+	group.DELETE(constants.STORAGE_ENDPOINT_PURGE_WITH_SECRET, uploadPrivate, h.PurgeWithSecret)
+
+	// tus
+	group.OPTIONS(constants.TUS_ENDPOINT_CREATE, tusResumable, h.TusOptions)
+	group.POST(constants.TUS_ENDPOINT_CREATE, tusResumable, uploadPublic, h.TusCreate)
+	group.OPTIONS(constants.TUS_ENDPOINT_UPLOAD, tusResumable, h.TusOptions)
+	group.HEAD(constants.TUS_ENDPOINT_UPLOAD, tusResumable, uploadPublic, h.TusHead)
+	group.PATCH(constants.TUS_ENDPOINT_UPLOAD, tusResumable, uploadPublic, h.TusPatch)
+	group.DELETE(constants.TUS_ENDPOINT_UPLOAD, tusResumable, uploadPublic, h.TusDelete)
 }
 
 // Upload godoc
@@ -53,11 +87,15 @@ func (h Handler) MapRoutes(group *gin.RouterGroup) {
 //	@Tags			Storage
 //	@Accept			mpfd
 //	@Produce		multipart/form-data
-//	@Param			id			query		string	false	"session id"
-//	@Param			url			formData	string	false	"file url"
-//	@Param			file		formData	file	false	"binary file"
-//	@Param			file_name	formData	string	false	"file name"
-//	@Success		201			{object}	models.UploadResponse
+//	@Param			id					query		string	false	"session id"
+//	@Param			url					formData	string	false	"file url"
+//	@Param			file				formData	file	false	"binary file"
+//	@Param			file_name			formData	string	false	"file name"
+//	@Param			life_time			formData	int64	false	"lifetime in seconds"
+//	@Param			expire_at			formData	string	false	"expire at (RFC3339)"
+//	@Param			checksum			formData	string	false	"checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted"
+//	@Param			checksum_algorithm	formData	string	false	"checksum algorithm (md5, sha1, sha256, crc32c), default sha256"
+//	@Success		201					{object}	models.UploadResponse
 //	@Router			/storage/upload [post]
 func (h Handler) Upload(ctx *gin.Context) {
 	maxSize := h.cfg.Upload.MaxSizeMB
@@ -82,13 +120,27 @@ func (h Handler) Upload(ctx *gin.Context) {
 			}
 		}
 	}
-	userId := int64(1)
+	lifeTime, expireAt, err := parseExpiry(ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	checksumAlgorithm, checksum, err := parseChecksum(ctx, ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	userId := authModel.GetPrincipal(ctx).UserId
 
 	req := &models.UploadRequest{
-		SessionId: id,
-		URL:       url,
-		File:      file,
-		FileName:  fileName,
+		SessionId:         id,
+		URL:               url,
+		File:              file,
+		FileName:          fileName,
+		LifeTime:          lifeTime,
+		ExpireAt:          expireAt,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
 	}
 
 	if err := req.Validate(); err != nil {
@@ -97,11 +149,72 @@ func (h Handler) Upload(ctx *gin.Context) {
 	}
 
 	res, err := h.usecase.Upload(ctx, userId, req)
+	if err != nil {
+		uploadError(ctx, err)
+		return
+	}
+
+	xhttp.Created(ctx, res)
+}
+
+// UploadStream godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Upload media by stream
+//	@Description	Upload media file as the raw request body, it is streamed to storage without buffering, secret is required for private upload
+//	@Tags			Storage
+//	@Accept			octet-stream
+//	@Produce		json
+//	@Param			id					query		string	false	"session id"
+//	@Param			secret				query		string	false	"secret"
+//	@Param			file_name			query		string	true	"file name with extension"
+//	@Param			life_time			query		int64	false	"lifetime in seconds"
+//	@Param			expire_at			query		string	false	"expire at (RFC3339)"
+//	@Param			checksum			query		string	false	"checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted"
+//	@Param			checksum_algorithm	query		string	false	"checksum algorithm (md5, sha1, sha256, crc32c), default sha256"
+//	@Success		201					{object}	models.UploadResponse
+//	@Router			/storage/upload/stream [put]
+func (h Handler) UploadStream(ctx *gin.Context) {
+	maxSize := h.cfg.Upload.MaxSizeMB
+	if ctx.Request.ContentLength > maxSize<<20 {
+		xhttp.BadRequest(ctx, fmt.Errorf("file size too large (max: %dMB)", maxSize))
+		return
+	}
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize<<20)
+
+	lifeTime, expireAt, err := parseExpiry(ctx.Query)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	checksumAlgorithm, checksum, err := parseChecksum(ctx, ctx.Query)
 	if err != nil {
 		xhttp.BadRequest(ctx, err)
 		return
 	}
 
+	userId := authModel.GetPrincipal(ctx).UserId
+	res, err := h.usecase.UploadStream(ctx, userId, &models.UploadStreamRequest{
+		SessionId:         ctx.Query("id"),
+		Secret:            ctx.Query("secret"),
+		FileName:          ctx.Query("file_name"),
+		Size:              ctx.Request.ContentLength,
+		Reader:            body,
+		LifeTime:          lifeTime,
+		ExpireAt:          expireAt,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
+	})
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			xhttp.BadRequest(ctx, fmt.Errorf("file size too large (max: %dMB)", maxSize))
+			return
+		}
+		uploadError(ctx, err)
+		return
+	}
+
 	xhttp.Created(ctx, res)
 }
 
@@ -113,13 +226,17 @@ func (h Handler) Upload(ctx *gin.Context) {
 //	@Tags			Storage
 //	@Accept			mpfd
 //	@Produce		json
-//	@Param			id				query		string	false	"session id"
-//	@Param			chunk			formData	file	true	"binary chunk"
-//	@Param			chunk_index		formData	int64	true	"chunk index"
-//	@Param			total_chunks	formData	int64	true	"total chunk"
-//	@Param			file_id			formData	string	false	"file id"
-//	@Param			file_name		formData	string	false	"file name"
-//	@Success		201				{object}	models.UploadChunkResponse
+//	@Param			id					query		string	false	"session id"
+//	@Param			chunk				formData	file	true	"binary chunk"
+//	@Param			chunk_index			formData	int64	true	"chunk index"
+//	@Param			total_chunks		formData	int64	true	"total chunk"
+//	@Param			file_id				formData	string	false	"file id"
+//	@Param			file_name			formData	string	false	"file name"
+//	@Param			life_time			formData	int64	false	"lifetime in seconds, used on first chunk"
+//	@Param			expire_at			formData	string	false	"expire at (RFC3339), used on first chunk"
+//	@Param			checksum			formData	string	false	"checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted"
+//	@Param			checksum_algorithm	formData	string	false	"checksum algorithm (md5, sha1, sha256, crc32c), default sha256"
+//	@Success		201					{object}	models.UploadChunkResponse
 //	@Router			/storage/upload/stage [post]
 func (h Handler) UploadChunk(ctx *gin.Context) {
 	maxSize := h.cfg.Upload.MaxSizeMB
@@ -152,17 +269,32 @@ func (h Handler) UploadChunk(ctx *gin.Context) {
 		return
 	}
 
-	userId := int64(1)
+	lifeTime, expireAt, err := parseExpiry(ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	checksumAlgorithm, checksum, err := parseChecksum(ctx, ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	userId := authModel.GetPrincipal(ctx).UserId
 	res, err := h.usecase.UploadChunk(ctx, userId, &models.UploadChunkRequest{
-		SessionId:   id,
-		FileId:      fileId,
-		FileName:    fileName,
-		Chunk:       chunk,
-		ChunkIndex:  chunkIndex,
-		TotalChunks: totalChunks,
+		SessionId:         id,
+		FileId:            fileId,
+		FileName:          fileName,
+		Chunk:             chunk,
+		ChunkIndex:        chunkIndex,
+		TotalChunks:       totalChunks,
+		LifeTime:          lifeTime,
+		ExpireAt:          expireAt,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
 	})
 	if err != nil {
-		xhttp.BadRequest(ctx, err)
+		uploadError(ctx, err)
 		return
 	}
 
@@ -190,52 +322,186 @@ func (h Handler) CommitChunk(ctx *gin.Context) {
 	}
 	req.SessionId = id
 
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	res, err := h.usecase.CommitChunk(ctx, userId, req)
 	if err != nil {
+		uploadError(ctx, err)
+		return
+	}
+
+	xhttp.Created(ctx, res)
+}
+
+// PresignUpload godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Presign upload media
+//	@Description	Reserve a file and get a short-lived write-only url to upload it directly to storage, secret is required for private upload
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret	query		string						false	"secret"
+//	@Param			body	body		models.PresignUploadRequest	true	"presign upload request"
+//	@Success		201		{object}	models.PresignUploadResponse
+//	@Router			/storage/upload/presign [post]
+func (h Handler) PresignUpload(ctx *gin.Context) {
+	secret := ctx.Query("secret")
+	req := &models.PresignUploadRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
 		xhttp.BadRequest(ctx, err)
 		return
 	}
+	req.Secret = secret
+
+	userId := authModel.GetPrincipal(ctx).UserId
+	res, err := h.usecase.PresignUpload(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Created(ctx, res)
+}
+
+// FinalizeUpload godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Finalize presigned upload media
+//	@Description	Verify a file uploaded with a presigned url, sniff its type and size and complete the upload, secret is required for private upload
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret	query		string							false	"secret"
+//	@Param			body	body		models.FinalizeUploadRequest	true	"finalize upload request"
+//	@Success		201		{object}	models.UploadResponse
+//	@Router			/storage/upload/finalize [post]
+func (h Handler) FinalizeUpload(ctx *gin.Context) {
+	secret := ctx.Query("secret")
+	req := &models.FinalizeUploadRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	req.Secret = secret
+
+	userId := authModel.GetPrincipal(ctx).UserId
+	res, err := h.usecase.FinalizeUpload(ctx, userId, req)
+	if err != nil {
+		uploadError(ctx, err)
+		return
+	}
 
 	xhttp.Created(ctx, res)
 }
 
+// UploadStatus godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Upload media chunk status
+//	@Description	Get received and missing chunk indices of a chunked upload, secret is required for private upload
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id	path		string	true	"file id"
+//	@Param			secret	query		string	false	"secret"
+//	@Success		200		{object}	models.UploadStatusResponse
+//	@Router			/storage/upload/{file_id}/status [get]
+func (h Handler) UploadStatus(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	res, err := h.usecase.UploadStatus(ctx, userId, &models.UploadStatusRequest{
+		FileId: fileId,
+		Secret: secret,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
 // UploadWithSecret godoc
 //
 //	@Security		ApiKeyAuth
 //	@Summary		Upload media with secret
-//	@Description	Upload media file (images, videos, etc.)
+//	@Description	Upload media file (images, videos, etc.), must provide file or url
 //	@Tags			Storage
 //	@Accept			mpfd
 //	@Produce		json
-//	@Param			id			query		string	false	"session id"
-//	@Param			secret		query		string	true	"secret"
-//	@Param			file		formData	file	true	"binary file"
-//	@Param			file_name	formData	string	false	"file name"
-//	@Success		201			{object}	models.UploadResponse
+//	@Param			id					query		string	false	"session id"
+//	@Param			secret				query		string	true	"secret"
+//	@Param			url					formData	string	false	"file url"
+//	@Param			file				formData	file	false	"binary file"
+//	@Param			file_name			formData	string	false	"file name"
+//	@Param			life_time			formData	int64	false	"lifetime in seconds"
+//	@Param			expire_at			formData	string	false	"expire at (RFC3339)"
+//	@Param			checksum			formData	string	false	"checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted"
+//	@Param			checksum_algorithm	formData	string	false	"checksum algorithm (md5, sha1, sha256, crc32c), default sha256"
+//	@Success		201					{object}	models.UploadResponse
 //	@Router			/storage/secret/upload [post]
 func (h Handler) UploadWithSecret(ctx *gin.Context) {
+	maxSize := h.cfg.Upload.MaxSizeMB
+	contentType := ctx.GetHeader("Content-Type")
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize<<20)
+
 	id := ctx.Query("id")
 	secret := ctx.Query("secret")
 	fileName := ctx.PostForm("file_name")
-	file, err := ctx.FormFile("file")
+	url := ctx.PostForm("url")
+	var file *multipart.FileHeader
+	if strings.Contains(contentType, "multipart/form-data") {
+		var err error
+		file, err = ctx.FormFile("file")
+		if err != nil {
+			if err.Error() != "http: no such file" {
+				if err.Error() == "multipart: NextPart: http: request body too large" {
+					xhttp.BadRequest(ctx, fmt.Errorf("file size too large (max: %dMB)", maxSize))
+				} else {
+					xhttp.BadRequest(ctx, err)
+				}
+				return
+			}
+		}
+	}
+
+	lifeTime, expireAt, err := parseExpiry(ctx.PostForm)
 	if err != nil {
 		xhttp.BadRequest(ctx, err)
 		return
 	}
-
-	userId := int64(1)
-	res, err := h.usecase.UploadWithSecret(ctx, userId, &models.UploadWithSecretRequest{
-		SessionId: id,
-		Secret:    secret,
-		File:      file,
-		FileName:  fileName,
-	})
+	checksumAlgorithm, checksum, err := parseChecksum(ctx, ctx.PostForm)
 	if err != nil {
 		xhttp.BadRequest(ctx, err)
 		return
 	}
 
+	userId := authModel.GetPrincipal(ctx).UserId
+
+	req := &models.UploadWithSecretRequest{
+		SessionId:         id,
+		Secret:            secret,
+		URL:               url,
+		File:              file,
+		FileName:          fileName,
+		LifeTime:          lifeTime,
+		ExpireAt:          expireAt,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
+	}
+
+	if err := req.Validate(); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	res, err := h.usecase.UploadWithSecret(ctx, userId, req)
+	if err != nil {
+		uploadError(ctx, err)
+		return
+	}
+
 	xhttp.Created(ctx, res)
 }
 
@@ -247,14 +513,18 @@ func (h Handler) UploadWithSecret(ctx *gin.Context) {
 //	@Tags			Storage
 //	@Accept			mpfd
 //	@Produce		json
-//	@Param			id				query		string	false	"session id"
-//	@Param			secret			query		string	true	"secret"
-//	@Param			chunk			formData	file	true	"binary chunk"
-//	@Param			chunk_index		formData	int64	true	"chunk index"
-//	@Param			total_chunks	formData	int64	true	"total chunk"
-//	@Param			file_id			formData	string	false	"file id"
-//	@Param			file_name		formData	string	false	"file name"
-//	@Success		201				{object}	models.UploadChunkResponse
+//	@Param			id					query		string	false	"session id"
+//	@Param			secret				query		string	true	"secret"
+//	@Param			chunk				formData	file	true	"binary chunk"
+//	@Param			chunk_index			formData	int64	true	"chunk index"
+//	@Param			total_chunks		formData	int64	true	"total chunk"
+//	@Param			file_id				formData	string	false	"file id"
+//	@Param			file_name			formData	string	false	"file name"
+//	@Param			life_time			formData	int64	false	"lifetime in seconds, used on first chunk"
+//	@Param			expire_at			formData	string	false	"expire at (RFC3339), used on first chunk"
+//	@Param			checksum			formData	string	false	"checksum (hex) to verify before storing, Content-MD5 header (base64) is also accepted"
+//	@Param			checksum_algorithm	formData	string	false	"checksum algorithm (md5, sha1, sha256, crc32c), default sha256"
+//	@Success		201					{object}	models.UploadChunkResponse
 //	@Router			/storage/secret/upload/stage [post]
 func (h Handler) UploadChunkWithSecret(ctx *gin.Context) {
 	maxSize := h.cfg.Upload.MaxSizeMB
@@ -288,18 +558,33 @@ func (h Handler) UploadChunkWithSecret(ctx *gin.Context) {
 		return
 	}
 
-	userId := int64(1)
+	lifeTime, expireAt, err := parseExpiry(ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	checksumAlgorithm, checksum, err := parseChecksum(ctx, ctx.PostForm)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	userId := authModel.GetPrincipal(ctx).UserId
 	res, err := h.usecase.UploadChunkWithSecret(ctx, userId, &models.UploadChunkWithSecretRequest{
-		SessionId:   id,
-		Secret:      secret,
-		FileId:      fileId,
-		FileName:    fileName,
-		Chunk:       chunk,
-		ChunkIndex:  chunkIndex,
-		TotalChunks: totalChunks,
+		SessionId:         id,
+		Secret:            secret,
+		FileId:            fileId,
+		FileName:          fileName,
+		Chunk:             chunk,
+		ChunkIndex:        chunkIndex,
+		TotalChunks:       totalChunks,
+		LifeTime:          lifeTime,
+		ExpireAt:          expireAt,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
 	})
 	if err != nil {
-		xhttp.BadRequest(ctx, err)
+		uploadError(ctx, err)
 		return
 	}
 
@@ -330,10 +615,10 @@ func (h Handler) CommitChunkWithSecret(ctx *gin.Context) {
 	req.SessionId = id
 	req.Secret = secret
 
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	res, err := h.usecase.CommitChunkWithSecret(ctx, userId, req)
 	if err != nil {
-		xhttp.BadRequest(ctx, err)
+		uploadError(ctx, err)
 		return
 	}
 
@@ -348,20 +633,23 @@ func (h Handler) CommitChunkWithSecret(ctx *gin.Context) {
 //	@Tags			Storage
 //	@Accept			json
 //	@Produce		json
-//	@Param			file_id	path		string	true	"file id"
-//	@Param			token	query		string	true	"token"
-//	@Param			secret	query		string	true	"secret"
-//	@Success		200		{object}	models.RequestDownloadResponse
+//	@Param			file_id		path		string	true	"file id"
+//	@Param			token		query		string	true	"token"
+//	@Param			secret		query		string	true	"secret"
+//	@Param			pin_code	query		string	true	"pin code"
+//	@Success		200			{object}	models.RequestDownloadResponse
 //	@Router			/storage/download/request/{file_id} [get]
 func (h Handler) RequestDownload(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	fileId := ctx.Param("file_id")
 	token := ctx.Query("token")
 	secret := ctx.Query("secret")
+	pinCode := ctx.Query("pin_code")
 	res, err := h.usecase.RequestDownload(ctx, userId, &models.RequestDownloadRequest{
-		FileId: fileId,
-		Token:  token,
-		Secret: secret,
+		FileId:  fileId,
+		Token:   token,
+		Secret:  secret,
+		PinCode: pinCode,
 	})
 	if err != nil {
 		xhttp.BadRequest(ctx, err)
@@ -387,7 +675,7 @@ func (h Handler) RequestDownload(ctx *gin.Context) {
 //	@Success		200			{object}	models.DownloadResponse
 //	@Router			/storage/download/{file_id} [get]
 func (h Handler) Download(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	fileId := ctx.Param("file_id")
 	token := ctx.Query("token")
 	secret := ctx.Query("secret")
@@ -423,7 +711,7 @@ func (h Handler) Download(ctx *gin.Context) {
 //	@Success		201		{object}	models.CreateSecretResponse
 //	@Router			/storage/secret [post]
 func (h Handler) CreateSecret(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	req := &models.CreateSecretRequest{}
 	if err := ctx.ShouldBindJSON(req); err != nil {
 		xhttp.BadRequest(ctx, err)
@@ -450,7 +738,7 @@ func (h Handler) CreateSecret(ctx *gin.Context) {
 //	@Success		200		{object}	models.RetrieveSecretResponse
 //	@Router			/storage/secret/retrieve [put]
 func (h Handler) RetrieveSecret(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	req := &models.RetrieveSecretRequest{}
 	if err := ctx.ShouldBindJSON(req); err != nil {
 		xhttp.BadRequest(ctx, err)
@@ -465,6 +753,60 @@ func (h Handler) RetrieveSecret(ctx *gin.Context) {
 	xhttp.Ok(ctx, res)
 }
 
+// RefreshSecret godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Refresh secret
+//	@Description	Exchange a refresh token for a new access token, the refresh token is rotated and can't be used again
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.RefreshSecretRequest	true	"refresh secret request"
+//	@Success		200		{object}	models.RefreshSecretResponse
+//	@Router			/storage/secret/refresh [post]
+func (h Handler) RefreshSecret(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &models.RefreshSecretRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.RefreshSecret(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// LogoutSecret godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Logout secret
+//	@Description	Revoke the session of a refresh token, or every session of the secret with all
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.LogoutSecretRequest	true	"logout secret request"
+//	@Success		200		{object}	models.LogoutSecretResponse
+//	@Router			/storage/secret/logout [post]
+func (h Handler) LogoutSecret(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &models.LogoutSecretRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.LogoutSecret(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
 // ResetPinCode godoc
 //
 //	@Security		ApiKeyAuth
@@ -477,7 +819,7 @@ func (h Handler) RetrieveSecret(ctx *gin.Context) {
 //	@Success		200
 //	@Router			/storage/secret/pin [put]
 func (h Handler) ResetPinCode(ctx *gin.Context) {
-	userId := int64(1)
+	userId := authModel.GetPrincipal(ctx).UserId
 	req := &models.ResetPinCodeRequest{}
 	if err := ctx.ShouldBindJSON(req); err != nil {
 		xhttp.BadRequest(ctx, err)
@@ -491,3 +833,250 @@ func (h Handler) ResetPinCode(ctx *gin.Context) {
 
 	xhttp.Ok(ctx, res)
 }
+
+// ListSecretFiles godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		List secret files
+//	@Description	List private files uploaded with secret
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret		query		string	true	"secret"
+//	@Param			pin_code	query		string	true	"pin code"
+//	@Param			page		query		int64	false	"page"
+//	@Param			size		query		int64	false	"size"
+//	@Param			sort_by		query		string	false	"sort by (file_name, file_size, type, ext, created_at)"
+//	@Param			order_by	query		string	false	"order by (asc, desc)"
+//	@Param			type		query		string	false	"mime type or family (e.g. image/png, image)"
+//	@Param			ext			query		string	false	"file extension (e.g. .png)"
+//	@Param			file_name	query		string	false	"file name contains"
+//	@Param			from		query		string	false	"created from (RFC3339)"
+//	@Param			to			query		string	false	"created to (RFC3339)"
+//	@Success		200			{object}	models.ListFileResponse
+//	@Router			/storage/secret/files [get]
+func (h Handler) ListSecretFiles(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &models.ListSecretFilesRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.ListSecretFiles(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// Delete godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Delete public media
+//	@Description	Move public media file to trash, it is purged after the retention
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id	path		string	true	"file id"
+//	@Param			token	query		string	true	"token"
+//	@Success		200		{object}	models.DeleteResponse
+//	@Router			/storage/file/{file_id} [delete]
+func (h Handler) Delete(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	token := ctx.Query("token")
+	res, err := h.usecase.Delete(ctx, userId, &models.DeleteRequest{
+		FileId: fileId,
+		Token:  token,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// DeleteWithSecret godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Delete private media
+//	@Description	Move private media file to trash with secret, it can be restored until purged
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id		path		string	true	"file id"
+//	@Param			secret		query		string	true	"secret"
+//	@Param			pin_code	query		string	true	"pin code"
+//	@Success		200			{object}	models.DeleteResponse
+//	@Router			/storage/secret/file/{file_id} [delete]
+func (h Handler) DeleteWithSecret(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	pinCode := ctx.Query("pin_code")
+	res, err := h.usecase.DeleteWithSecret(ctx, userId, &models.DeleteWithSecretRequest{
+		FileId:  fileId,
+		Secret:  secret,
+		PinCode: pinCode,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// ListSecretTrash godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		List secret trash
+//	@Description	List private files in trash
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			secret		query		string	true	"secret"
+//	@Param			pin_code	query		string	true	"pin code"
+//	@Param			page		query		int64	false	"page"
+//	@Param			size		query		int64	false	"size"
+//	@Param			sort_by		query		string	false	"sort by (file_name, file_size, type, ext, created_at, deleted_at)"
+//	@Param			order_by	query		string	false	"order by (asc, desc)"
+//	@Param			type		query		string	false	"mime type or family (e.g. image/png, image)"
+//	@Param			ext			query		string	false	"file extension (e.g. .png)"
+//	@Param			file_name	query		string	false	"file name contains"
+//	@Param			from		query		string	false	"created from (RFC3339)"
+//	@Param			to			query		string	false	"created to (RFC3339)"
+//	@Success		200			{object}	models.ListFileResponse
+//	@Router			/storage/secret/trash [get]
+func (h Handler) ListSecretTrash(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	req := &models.ListSecretFilesRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+	res, err := h.usecase.ListSecretTrash(ctx, userId, req)
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// RestoreWithSecret godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Restore private media
+//	@Description	Restore private media file from trash with secret
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id	path		string	true	"file id"
+//	@Param			secret	query		string	true	"secret"
+//	@Success		200		{object}	models.RestoreResponse
+//	@Router			/storage/secret/trash/{file_id}/restore [post]
+func (h Handler) RestoreWithSecret(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	res, err := h.usecase.RestoreWithSecret(ctx, userId, &models.RestoreWithSecretRequest{
+		FileId: fileId,
+		Secret: secret,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// PurgeWithSecret godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Purge private media
+//	@Description	Permanently delete private media file and its metadata from trash with secret
+//	@Tags			Storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			file_id		path		string	true	"file id"
+//	@Param			secret		query		string	true	"secret"
+//	@Param			pin_code	query		string	true	"pin code"
+//	@Success		200			{object}	models.DeleteResponse
+//	@Router			/storage/secret/trash/{file_id} [delete]
+func (h Handler) PurgeWithSecret(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	secret := ctx.Query("secret")
+	pinCode := ctx.Query("pin_code")
+	res, err := h.usecase.PurgeWithSecret(ctx, userId, &models.DeleteWithSecretRequest{
+		FileId:  fileId,
+		Secret:  secret,
+		PinCode: pinCode,
+	})
+	if err != nil {
+		xhttp.BadRequest(ctx, err)
+		return
+	}
+
+	xhttp.Ok(ctx, res)
+}
+
+// uploadError surfaces the reason of an upload policy rejection
+func uploadError(ctx *gin.Context, err error) {
+	var policyErr *models.PolicyError
+	if errors.As(err, &policyErr) {
+		xhttp.BadRequestWithReason(ctx, policyErr.Reason, err)
+		return
+	}
+	xhttp.BadRequest(ctx, err)
+}
+
+// parseChecksum reads the optional checksum (hex) of an upload from form or query values,
+// Content-MD5 (base64) is used when no checksum is given
+func parseChecksum(ctx *gin.Context, value func(key string) string) (string, string, error) {
+	algorithm := strings.ToLower(value("checksum_algorithm"))
+	if algorithm == "" {
+		algorithm = constants.CHECKSUM_ALGORITHM_SHA256
+	}
+	if checksum := value("checksum"); checksum != "" {
+		return algorithm, strings.ToLower(checksum), nil
+	}
+
+	if contentMD5 := ctx.GetHeader(constants.HEADER_CONTENT_MD5); contentMD5 != "" {
+		digest, err := base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid content md5")
+		}
+		return constants.CHECKSUM_ALGORITHM_MD5, hex.EncodeToString(digest), nil
+	}
+
+	return "", "", nil
+}
+
+// parseExpiry reads the optional lifetime (in seconds) or expire at (RFC3339) of an upload from form or query values
+func parseExpiry(value func(key string) string) (int64, time.Time, error) {
+	var lifeTime int64
+	if lifeTimeStr := value("life_time"); lifeTimeStr != "" {
+		var err error
+		lifeTime, err = strconv.ParseInt(lifeTimeStr, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid life time")
+		}
+	}
+
+	var expireAt time.Time
+	if expireAtStr := value("expire_at"); expireAtStr != "" {
+		var err error
+		expireAt, err = time.Parse(time.RFC3339, expireAtStr)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid expire at")
+		}
+	}
+
+	return lifeTime, expireAt, nil
+}
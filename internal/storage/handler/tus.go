@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"medioa/constants"
+	authModel "medioa/internal/auth/models"
+	"medioa/internal/storage/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TusOptions godoc
+//
+//	@Summary		Tus discovery
+//	@Description	Report supported tus version and extensions
+//	@Tags			Tus
+//	@Success		204
+//	@Router			/tus [options]
+func (h Handler) TusOptions(ctx *gin.Context) {
+	ctx.Header(constants.TUS_HEADER_VERSION, constants.TUS_VERSION)
+	ctx.Header(constants.TUS_HEADER_EXTENSION, constants.TUS_EXTENSION)
+	ctx.Header(constants.TUS_HEADER_CHECKSUM_ALGORITHM, constants.TUS_CHECKSUM_ALGORITHM)
+	ctx.Header(constants.TUS_HEADER_MAX_SIZE, strconv.FormatInt(h.cfg.Upload.MaxSizeMB<<20, 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// TusCreate godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Tus create upload
+//	@Description	Create a resumable upload (metadata: filename, filetype, secret, life_time, expire_at)
+//	@Tags			Tus
+//	@Param			Tus-Resumable	header	string	true	"tus version (1.0.0)"
+//	@Param			Upload-Length	header	int64	true	"upload length in bytes"
+//	@Param			Upload-Metadata	header	string	false	"upload metadata"
+//	@Success		201
+//	@Router			/tus [post]
+func (h Handler) TusCreate(ctx *gin.Context) {
+	uploadLength, err := strconv.ParseInt(ctx.GetHeader(constants.TUS_HEADER_UPLOAD_LENGTH), 10, 64)
+	if err != nil {
+		tusError(ctx, fmt.Errorf("invalid upload length"))
+		return
+	}
+
+	metadata, err := parseTusMetadata(ctx.GetHeader(constants.TUS_HEADER_UPLOAD_METADATA))
+	if err != nil {
+		tusError(ctx, err)
+		return
+	}
+
+	var lifeTime int64
+	if lifeTimeStr := metadata[constants.TUS_METADATA_LIFE_TIME]; lifeTimeStr != "" {
+		lifeTime, err = strconv.ParseInt(lifeTimeStr, 10, 64)
+		if err != nil {
+			tusError(ctx, fmt.Errorf("invalid life time"))
+			return
+		}
+	}
+
+	var expireAt time.Time
+	if expireAtStr := metadata[constants.TUS_METADATA_EXPIRE_AT]; expireAtStr != "" {
+		expireAt, err = time.Parse(time.RFC3339, expireAtStr)
+		if err != nil {
+			tusError(ctx, fmt.Errorf("invalid expire at"))
+			return
+		}
+	}
+
+	userId := authModel.GetPrincipal(ctx).UserId
+	res, err := h.usecase.TusCreate(ctx, userId, &models.TusCreateRequest{
+		UploadLength: uploadLength,
+		FileName:     metadata[constants.TUS_METADATA_FILE_NAME],
+		FileType:     metadata[constants.TUS_METADATA_FILE_TYPE],
+		Secret:       metadata[constants.TUS_METADATA_SECRET],
+		LifeTime:     lifeTime,
+		ExpireAt:     expireAt,
+	})
+	if err != nil {
+		tusError(ctx, err)
+		return
+	}
+
+	// the token proves follow-up requests come from the creator, tus clients keep the query of the location
+	ctx.Header("Location", strings.TrimSuffix(ctx.Request.URL.Path, "/")+"/"+res.FileId+"?token="+url.QueryEscape(res.Token))
+	ctx.Header(constants.TUS_HEADER_UPLOAD_OFFSET, strconv.FormatInt(res.UploadOffset, 10))
+	ctx.Status(http.StatusCreated)
+}
+
+// TusHead godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Tus upload offset
+//	@Description	Get current offset of a resumable upload
+//	@Tags			Tus
+//	@Param			file_id			path	string	true	"file id"
+//	@Param			token			query	string	true	"upload token of the location"
+//	@Param			Tus-Resumable	header	string	true	"tus version (1.0.0)"
+//	@Param			Upload-Secret	header	string	false	"secret of a private upload"
+//	@Success		200
+//	@Router			/tus/{file_id} [head]
+func (h Handler) TusHead(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	res, err := h.usecase.TusHead(ctx, userId, &models.TusHeadRequest{
+		FileId: fileId,
+		Token:  ctx.Query("token"),
+		Secret: ctx.GetHeader(constants.TUS_HEADER_UPLOAD_SECRET),
+	})
+	if err != nil {
+		tusError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header(constants.TUS_HEADER_UPLOAD_OFFSET, strconv.FormatInt(res.UploadOffset, 10))
+	ctx.Header(constants.TUS_HEADER_UPLOAD_LENGTH, strconv.FormatInt(res.UploadLength, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// TusPatch godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Tus append upload
+//	@Description	Append bytes to a resumable upload at the given offset, the upload is committed on the last byte
+//	@Tags			Tus
+//	@Accept			application/offset+octet-stream
+//	@Param			file_id			path	string	true	"file id"
+//	@Param			token			query	string	true	"upload token of the location"
+//	@Param			Tus-Resumable	header	string	true	"tus version (1.0.0)"
+//	@Param			Upload-Secret	header	string	false	"secret of a private upload"
+//	@Param			Upload-Offset	header	int64	true	"upload offset"
+//	@Param			Upload-Checksum	header	string	false	"checksum (md5, sha1, sha256, crc32c)"
+//	@Success		204
+//	@Router			/tus/{file_id} [patch]
+func (h Handler) TusPatch(ctx *gin.Context) {
+	if ctx.ContentType() != constants.TUS_CONTENT_TYPE {
+		ctx.String(http.StatusUnsupportedMediaType, "content type must be %s", constants.TUS_CONTENT_TYPE)
+		return
+	}
+
+	uploadOffset, err := strconv.ParseInt(ctx.GetHeader(constants.TUS_HEADER_UPLOAD_OFFSET), 10, 64)
+	if err != nil {
+		tusError(ctx, fmt.Errorf("invalid upload offset"))
+		return
+	}
+
+	var algorithm string
+	var checksum []byte
+	if uploadChecksum := ctx.GetHeader(constants.TUS_HEADER_UPLOAD_CHECKSUM); uploadChecksum != "" {
+		var encoded string
+		var found bool
+		algorithm, encoded, found = strings.Cut(uploadChecksum, " ")
+		if !found {
+			tusError(ctx, models.ErrTusInvalidChecksum)
+			return
+		}
+		checksum, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			tusError(ctx, models.ErrTusInvalidChecksum)
+			return
+		}
+	}
+
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	res, err := h.usecase.TusPatch(ctx, userId, &models.TusPatchRequest{
+		FileId:            fileId,
+		Token:             ctx.Query("token"),
+		Secret:            ctx.GetHeader(constants.TUS_HEADER_UPLOAD_SECRET),
+		UploadOffset:      uploadOffset,
+		ChecksumAlgorithm: algorithm,
+		Checksum:          checksum,
+		MaxSize:           h.cfg.Upload.MaxSizeMB << 20,
+		Body:              ctx.Request.Body,
+	})
+	if err != nil {
+		tusError(ctx, err)
+		return
+	}
+
+	ctx.Header(constants.TUS_HEADER_UPLOAD_OFFSET, strconv.FormatInt(res.UploadOffset, 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// TusDelete godoc
+//
+//	@Security		ApiKeyAuth
+//	@Summary		Tus terminate upload
+//	@Description	Terminate an unfinished resumable upload and remove its staged data
+//	@Tags			Tus
+//	@Param			file_id			path	string	true	"file id"
+//	@Param			token			query	string	true	"upload token of the location"
+//	@Param			Tus-Resumable	header	string	true	"tus version (1.0.0)"
+//	@Param			Upload-Secret	header	string	false	"secret of a private upload"
+//	@Success		204
+//	@Router			/tus/{file_id} [delete]
+func (h Handler) TusDelete(ctx *gin.Context) {
+	userId := authModel.GetPrincipal(ctx).UserId
+	fileId := ctx.Param("file_id")
+	if err := h.usecase.TusDelete(ctx, userId, &models.TusDeleteRequest{
+		FileId: fileId,
+		Token:  ctx.Query("token"),
+		Secret: ctx.GetHeader(constants.TUS_HEADER_UPLOAD_SECRET),
+	}); err != nil {
+		tusError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// tusResumable checks the protocol version of tus requests and stamps it on responses.
+func tusResumable(ctx *gin.Context) {
+	ctx.Header(constants.TUS_HEADER_RESUMABLE, constants.TUS_VERSION)
+	if ctx.Request.Method == http.MethodOptions {
+		ctx.Next()
+		return
+	}
+	if ctx.GetHeader(constants.TUS_HEADER_RESUMABLE) != constants.TUS_VERSION {
+		ctx.Header(constants.TUS_HEADER_VERSION, constants.TUS_VERSION)
+		ctx.AbortWithStatus(http.StatusPreconditionFailed)
+		return
+	}
+	ctx.Next()
+}
+
+func tusError(ctx *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, models.ErrTusNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrTusOffsetMismatch):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrTusChecksumMismatch):
+		status = constants.TUS_STATUS_CHECKSUM_MISMATCH
+	case errors.Is(err, models.ErrTusUploadCompleted):
+		status = http.StatusForbidden
+	case errors.Is(err, models.ErrTusTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	}
+	ctx.String(status, err.Error())
+}
+
+// parseTusMetadata decodes "key base64value,key base64value" pairs
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("invalid upload metadata")
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload metadata")
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTusMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", header: "", want: map[string]string{}},
+		{name: "single pair", header: "filename dGVzdC5wbmc=", want: map[string]string{"filename": "test.png"}},
+		{
+			name:   "multiple pairs",
+			header: "filename dGVzdC5wbmc=, filetype aW1hZ2UvcG5n",
+			want:   map[string]string{"filename": "test.png", "filetype": "image/png"},
+		},
+		{name: "key without value", header: "is_confidential", want: map[string]string{"is_confidential": ""}},
+		{name: "invalid base64", header: "filename !!!", wantErr: true},
+		{name: "empty pair", header: "filename dGVzdC5wbmc=,", wantErr: true},
+		{name: "only separators", header: ",", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTusMetadata(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTusMetadata(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTusMetadata(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
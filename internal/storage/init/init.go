@@ -2,7 +2,10 @@ package init
 
 import (
 	"medioa/config"
+	initAuth "medioa/internal/auth/init"
 	initAzBlob "medioa/internal/azblob/init"
+	initLockout "medioa/internal/lockout/init"
+	initScanner "medioa/internal/scanner/init"
 	initSecret "medioa/internal/secret/init"
 	"medioa/internal/storage/handler"
 	"medioa/internal/storage/repository"
@@ -23,12 +26,15 @@ func NewInit(
 	lib *commonModel.Lib,
 	initSecret *initSecret.Init,
 	initAzBlob *initAzBlob.Init,
+	initScanner *initScanner.Init,
+	initAuth *initAuth.Init,
+	initLockout *initLockout.Init,
 ) *Init {
 	// repository := repository.InitRepo(lib)
 	repository := repository.InitMongo(cfg, lib)
 	service := service.InitService(cfg, lib, repository)
-	usecase := usecase.InitUsecase(cfg, service, initSecret.Service, initAzBlob.Service)
-	handler := handler.InitHandler(cfg, lib, usecase)
+	usecase := usecase.InitUsecase(cfg, service, initSecret.Service, initSecret.SessionService, initAzBlob.Service, initScanner.Backend, initLockout.Service)
+	handler := handler.InitHandler(cfg, lib, usecase, initAuth.Middleware)
 	return &Init{
 		Repository: repository,
 		Service:    service,
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type ListSecretsRequest struct {
+	Secret   string `form:"secret"`
+	Page     int64  `form:"page"`
+	Size     int64  `form:"size"`
+	SortBy   string `form:"sort_by"`
+	OrderBy  string `form:"order_by"`
+	Username string `form:"username"`
+}
+
+type SecretResponse struct {
+	UserId    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Type      string    `json:"type"`
+	IsMaster  bool      `json:"is_master"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListSecretResponse struct {
+	Page    int64             `json:"page"`
+	Size    int64             `json:"size"`
+	Count   int64             `json:"count"`
+	Records []*SecretResponse `json:"records"`
+}
+
+type ListFilesRequest struct {
+	Secret   string    `form:"secret"`
+	Page     int64     `form:"page"`
+	Size     int64     `form:"size"`
+	SortBy   string    `form:"sort_by"`
+	OrderBy  string    `form:"order_by"`
+	SecretId string    `form:"secret_id"`
+	Type     string    `form:"type"`
+	Ext      string    `form:"ext"`
+	FileName string    `form:"file_name"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Deleted  bool      `form:"deleted"` // list the trash instead
+}
+
+type AdminFileRequest struct {
+	FileId  string `json:"file_id"`
+	Secret  string `json:"secret"`
+	PinCode string `json:"pin_code"`
+}
+
+type ResetSecretPasswordRequest struct {
+	SecretId      string
+	Secret        string
+	MasterPinCode string
+	Password      string `json:"password"`
+}
+
+type ResetSecretPinCodeRequest struct {
+	SecretId      string
+	Secret        string
+	MasterPinCode string
+	PinCode       string `json:"pin_code"`
+}
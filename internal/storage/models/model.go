@@ -13,16 +13,27 @@ import (
 type RequestParams struct {
 	commonModel.RequestParams
 	ConfigQuery      int
-	Id               int
+	Id               int64
 	UUID             string
 	DownloadUrl      string
 	DownloadPassword string
 	Type             string
 	Token            string
 	Ext              string
+	FileName         string
 	LifeTime         int64
 	SecretId         string
 	CreatedBy        int64
+	CreatedFrom      time.Time
+	CreatedTo        time.Time
+	IsDeleted        *bool
+	DeletedBefore    time.Time
+	ExpiredBefore    time.Time
+	IsPending        *bool
+	IsReleasing      *bool
+	Sha256           string
+	BlobName         string
+	HasSecret        *bool
 }
 
 func (r *RequestParams) trimSpace() {
@@ -30,6 +41,7 @@ func (r *RequestParams) trimSpace() {
 	r.Type = strings.TrimSpace(r.Type)
 	r.Token = strings.TrimSpace(r.Token)
 	r.Ext = strings.TrimSpace(r.Ext)
+	r.FileName = strings.TrimSpace(r.FileName)
 	r.SecretId = strings.TrimSpace(r.SecretId)
 }
 func (r *RequestParams) ToMap() map[string]any {
@@ -38,6 +50,12 @@ func (r *RequestParams) ToMap() map[string]any {
 	if strings.ToLower(r.OrderBy) != constants.SORT_ORDER_ASC {
 		r.OrderBy = constants.SORT_ORDER_DESC
 	}
+	if r.Page <= 0 {
+		r.Page = constants.DEFAULT_PAGE
+	}
+	if r.Size <= 0 {
+		r.Size = constants.DEFAULT_SIZE
+	}
 	return map[string]any{
 		constants.FIELD_STORAGE_ID:                r.Id,
 		constants.FIELD_STORAGE_UUID:              r.UUID,
@@ -47,8 +65,19 @@ func (r *RequestParams) ToMap() map[string]any {
 		constants.FIELD_STORAGE_TOKEN:             r.Token,
 		constants.FIELD_STORAGE_LIFE_TIME:         r.LifeTime,
 		constants.FIELD_STORAGE_EXT:               r.Ext,
+		constants.FIELD_STORAGE_FILE_NAME:         r.FileName,
 		constants.FIELD_STORAGE_SECRET_ID:         r.SecretId,
 		constants.FIELD_STORAGE_CREATED_BY:        r.CreatedBy,
+		constants.FIELD_STORAGE_CREATED_FROM:      r.CreatedFrom,
+		constants.FIELD_STORAGE_CREATED_TO:        r.CreatedTo,
+		constants.FIELD_STORAGE_IS_DELETED:        r.IsDeleted,
+		constants.FIELD_STORAGE_DELETED_BEFORE:    r.DeletedBefore,
+		constants.FIELD_STORAGE_EXPIRED_BEFORE:    r.ExpiredBefore,
+		constants.FIELD_STORAGE_IS_PENDING:        r.IsPending,
+		constants.FIELD_STORAGE_IS_RELEASING:      r.IsReleasing,
+		constants.FIELD_STORAGE_SHA256:            r.Sha256,
+		constants.FIELD_STORAGE_BLOB_NAME:         r.BlobName,
+		constants.FIELD_STORAGE_HAS_SECRET:        r.HasSecret,
 		constants.FIELD_PAGE:                      r.Page,
 		constants.FIELD_SIZE:                      r.Size,
 		constants.FIELD_ORDER_BY:                  r.OrderBy,
@@ -58,21 +87,36 @@ func (r *RequestParams) ToMap() map[string]any {
 }
 
 type Response struct {
-	Id               int64     `json:"id"`
-	UUID             string    `json:"uuid"`
-	DownloadUrl      string    `json:"download_url"`
-	DownloadPassword string    `json:"download_password"`
-	Type             string    `json:"type"`
-	Token            string    `json:"token"`
-	LifeTime         int64     `json:"life_time"`
-	FileName         string    `json:"file_name"`
-	FileSize         int64     `json:"file_size"`
-	Ext              string    `json:"ext"`
-	SecretId         string    `json:"secret_id"`
-	CreatedBy        int64     `json:"created_by"`
-	CreatedAt        time.Time `json:"created_at"`
-	ChunkIds         []string  `json:"chunk_ids"`
-	TotalChunks      int64     `json:"total_chunks"`
+	Id               int64      `json:"id"`
+	UUID             string     `json:"uuid"`
+	DownloadUrl      string     `json:"download_url"`
+	DownloadPassword string     `json:"download_password"`
+	Type             string     `json:"type"`
+	Token            string     `json:"token"`
+	LifeTime         int64      `json:"life_time"`
+	FileName         string     `json:"file_name"`
+	FileSize         int64      `json:"file_size"`
+	Ext              string     `json:"ext"`
+	SecretId         string     `json:"secret_id"`
+	CreatedBy        int64      `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ChunkIds         []string   `json:"chunk_ids"`
+	TotalChunks      int64      `json:"total_chunks"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+	ExpiredAt        time.Time  `json:"expired_at"`
+	UploadOffset     int64      `json:"upload_offset"`
+	UploadLength     int64      `json:"upload_length"`
+	UploadedSize     int64      `json:"uploaded_size"`
+	Sha256           string     `json:"sha256"`
+	BlobName         string     `json:"blob_name"`
+	UploadId         string     `json:"upload_id"` // staged upload of the backend, e.g. s3 multipart upload
+	HashState        []byte     `json:"-"`         // sha256 state of the first hashed size bytes
+	HashedSize       int64      `json:"hashed_size"`
+	HashedChunks     int64      `json:"hashed_chunks"`
+	ChunkWrites      int64      `json:"chunk_writes"` // staged chunks, a chunk is hashed only if none was staged meanwhile
+	ScanStatus       string     `json:"scan_status"`
+	IsPending        bool       `json:"is_pending"`   // reserved before any content, e.g. presigned and tus uploads
+	IsReleasing      bool       `json:"is_releasing"` // deleted, its blob is not released yet
 }
 
 type SaveRequest struct {
@@ -91,6 +135,19 @@ type SaveRequest struct {
 	TotalChunks      int64
 	CreatedBy        int64
 	CreatedAt        time.Time
+	DeletedAt        *time.Time
+	ExpiredAt        time.Time
+	UploadOffset     int64
+	UploadLength     int64
+	UploadedSize     int64
+	Sha256           string
+	BlobName         string
+	UploadId         string
+	HashState        []byte
+	HashedSize       int64
+	ScanStatus       string
+	IsPending        *bool
+	IsReleasing      *bool
 }
 
 type ListPaging struct {
@@ -98,7 +155,35 @@ type ListPaging struct {
 	Records []*Response
 }
 
+type BlobStats struct {
+	Files        int64 `bson:"files"`
+	Blobs        int64 `bson:"blobs"`
+	LogicalSize  int64 `bson:"logical_size"`
+	PhysicalSize int64 `bson:"physical_size"`
+}
+
+// LockUploadRequest leases a tus upload at its offset to a single PATCH
+type LockUploadRequest struct {
+	Id           string
+	UploadOffset int64
+	LockedUntil  time.Time
+}
+
+// HashChunkRequest folds the next chunk into the running checksum, it applies only if no other
+// chunk was staged since the chunk was read
+type HashChunkRequest struct {
+	Id          string
+	ChunkIndex  int64
+	ChunkSize   int64
+	ChunkWrites int64
+	HashState   []byte
+}
+
 type AddChunkRequest struct {
-	Id      string
-	ChunkId string
+	Id          string
+	ChunkIndex  int64
+	ChunkId     string
+	ChunkSize   int64
+	TotalChunks int64
+	Type        string // sniffed from chunk 0 only
 }
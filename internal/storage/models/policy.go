@@ -0,0 +1,11 @@
+package models
+
+// PolicyError is returned when an upload is rejected by the upload policy
+type PolicyError struct {
+	Reason  string
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Message
+}
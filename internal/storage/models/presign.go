@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type PresignUploadRequest struct {
+	Secret   string    `json:"secret" swaggerignore:"true"`
+	FileName string    `json:"file_name"`
+	LifeTime int64     `json:"life_time"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+type PresignUploadResponse struct {
+	FileId    string            `json:"file_id"`
+	UploadUrl string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiredAt time.Time         `json:"expired_at"`
+}
+
+type FinalizeUploadRequest struct {
+	Secret string `json:"secret" swaggerignore:"true"`
+	FileId string `json:"file_id"`
+}
@@ -8,8 +8,8 @@ type CreateSecretRequest struct {
 }
 
 type CreateSecretResponse struct {
-	UserId      string `json:"user_id"`
-	AccessToken string `json:"access_token"`
+	UserId string `json:"user_id"`
+	SecretTokenResponse
 }
 
 type RetrieveSecretRequest struct {
@@ -18,11 +18,41 @@ type RetrieveSecretRequest struct {
 }
 
 type RetrieveSecretResponse struct {
-	UserId      string `json:"user_id"`
-	AccessToken string `json:"access_token"`
+	UserId string `json:"user_id"`
+	SecretTokenResponse
+}
+
+// SecretTokenResponse is a session of a secret, the access token is a short-lived jwt
+// and the refresh token is rotated on every refresh
+type SecretTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"` // in seconds
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` // in seconds
+}
+
+type RefreshSecretRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshSecretResponse struct {
+	UserId string `json:"user_id"`
+	SecretTokenResponse
+}
+
+type LogoutSecretRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"` // revoke every session of the secret
+}
+
+type LogoutSecretResponse struct {
+	Revoked int64 `json:"revoked"`
 }
 
+// ResetPinCodeRequest must prove the secret with the old pin code or the password
 type ResetPinCodeRequest struct {
 	AccessToken string `json:"access_token"`
+	OldPinCode  string `json:"old_pin_code"`
+	Password    string `json:"password"`
 	NewPinCode  string `json:"new_pin_code"`
 }
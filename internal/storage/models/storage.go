@@ -2,8 +2,10 @@ package models
 
 import (
 	"errors"
+	"io"
 	azBlobModel "medioa/internal/azblob/models"
 	"medioa/pkg/xtype"
+	"time"
 )
 
 type GetFileInfoRequest struct {
@@ -11,17 +13,60 @@ type GetFileInfoRequest struct {
 }
 
 type GetFileInfoResponse struct {
-	FileId    string `json:"file_id"`
-	FileName  string `json:"file_name"`
-	FileSize  int64  `json:"file_size"`
-	HasSecret bool   `json:"has_secret"`
+	FileId     string `json:"file_id"`
+	FileName   string `json:"file_name"`
+	FileSize   int64  `json:"file_size"`
+	HasSecret  bool   `json:"has_secret"`
+	Sha256     string `json:"sha256"`
+	ScanStatus string `json:"scan_status"`
+}
+
+type ListSecretFilesRequest struct {
+	Secret   string    `form:"secret"`
+	PinCode  string    `form:"pin_code"`
+	Page     int64     `form:"page"`
+	Size     int64     `form:"size"`
+	SortBy   string    `form:"sort_by"`
+	OrderBy  string    `form:"order_by"`
+	Type     string    `form:"type"`
+	Ext      string    `form:"ext"`
+	FileName string    `form:"file_name"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type FileResponse struct {
+	FileId     string     `json:"file_id"`
+	FileName   string     `json:"file_name"`
+	FileSize   int64      `json:"file_size"`
+	Type       string     `json:"type"`
+	Ext        string     `json:"ext"`
+	Token      string     `json:"token"`
+	Url        string     `json:"url"`
+	SecretId   string     `json:"secret_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Sha256     string     `json:"sha256"`
+	ScanStatus string     `json:"scan_status"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+}
+
+type ListFileResponse struct {
+	Page    int64           `json:"page"`
+	Size    int64           `json:"size"`
+	Count   int64           `json:"count"`
+	Records []*FileResponse `json:"records"`
 }
 
 type UploadRequest struct {
-	SessionId string     `json:"session_id"`
-	File      xtype.File `json:"file"`
-	URL       string     `json:"url"`
-	FileName  string     `json:"file_name"`
+	SessionId         string     `json:"session_id"`
+	File              xtype.File `json:"file"`
+	URL               string     `json:"url"`
+	FileName          string     `json:"file_name"`
+	LifeTime          int64      `json:"life_time"`
+	ExpireAt          time.Time  `json:"expire_at"`
+	ChecksumAlgorithm string     `json:"checksum_algorithm"`
+	Checksum          string     `json:"checksum"`
 }
 
 func (r *UploadRequest) Validate() error {
@@ -33,7 +78,8 @@ func (r *UploadRequest) Validate() error {
 
 func (r *UploadRequest) ToURLRequest() *azBlobModel.UploadURLRequest {
 	return &azBlobModel.UploadURLRequest{
-		URL: r.URL,
+		SessionId: r.SessionId,
+		URL:       r.URL,
 	}
 }
 
@@ -45,18 +91,23 @@ func (r *UploadRequest) ToBlobRequest() *azBlobModel.UploadBlobRequest {
 }
 
 type UploadChunkRequest struct {
-	SessionId   string     `json:"session_id"`
-	FileId      string     `json:"file_id"`
-	FileName    string     `json:"file_name"`
-	Chunk       xtype.File `json:"chunk"`
-	ChunkIndex  int64      `json:"chunk_index"`
-	TotalChunks int64      `json:"total_chunks"`
+	SessionId         string     `json:"session_id"`
+	FileId            string     `json:"file_id"`
+	FileName          string     `json:"file_name"`
+	Chunk             xtype.File `json:"chunk"`
+	ChunkIndex        int64      `json:"chunk_index"`
+	TotalChunks       int64      `json:"total_chunks"`
+	LifeTime          int64      `json:"life_time"`
+	ExpireAt          time.Time  `json:"expire_at"`
+	ChecksumAlgorithm string     `json:"checksum_algorithm"`
+	Checksum          string     `json:"checksum"`
 }
 
-func (r *UploadChunkRequest) ToBlobRequest(token string) *azBlobModel.UploadChunkRequest {
+func (r *UploadChunkRequest) ToBlobRequest(token, uploadId string) *azBlobModel.UploadChunkRequest {
 	return &azBlobModel.UploadChunkRequest{
 		SessionId:   r.SessionId,
 		Token:       token,
+		UploadId:    uploadId,
 		FileName:    r.FileName,
 		Chunk:       r.Chunk,
 		ChunkIndex:  r.ChunkIndex,
@@ -76,19 +127,67 @@ type CommitChunkRequest struct {
 }
 
 type CommitChunkResponse struct {
-	Url      string `json:"url"`
-	Token    string `json:"token"`
-	Ext      string `json:"ext"`
-	FileId   string `json:"file_id"`
-	FileName string `json:"file_name"`
-	FileSize int64  `json:"file_size"`
+	Url        string `json:"url"`
+	Token      string `json:"token"`
+	Ext        string `json:"ext"`
+	FileId     string `json:"file_id"`
+	FileName   string `json:"file_name"`
+	FileSize   int64  `json:"file_size"`
+	Sha256     string `json:"sha256"`
+	ScanStatus string `json:"scan_status"`
+}
+
+type UploadStatusRequest struct {
+	FileId string `json:"file_id"`
+	Secret string `json:"secret"`
+}
+
+type UploadStatusResponse struct {
+	FileId       string  `json:"file_id"`
+	Committed    bool    `json:"committed"`
+	Received     []int64 `json:"received"`
+	Missing      []int64 `json:"missing"`
+	ReceivedSize int64   `json:"received_size"`
+	TotalChunks  int64   `json:"total_chunks"`
+}
+
+type UploadStreamRequest struct {
+	SessionId         string
+	Secret            string
+	FileName          string
+	Size              int64 // -1 when unknown
+	Reader            io.Reader
+	LifeTime          int64
+	ExpireAt          time.Time
+	ChecksumAlgorithm string
+	Checksum          string
 }
 
 type UploadWithSecretRequest struct {
-	SessionId string
-	Secret    string
-	File      xtype.File
-	FileName  string
+	SessionId         string
+	Secret            string
+	File              xtype.File
+	URL               string
+	FileName          string
+	LifeTime          int64
+	ExpireAt          time.Time
+	ChecksumAlgorithm string
+	Checksum          string
+}
+
+func (r *UploadWithSecretRequest) Validate() error {
+	if r.File == nil && r.URL == "" {
+		return errors.New("file or url is required")
+	}
+	return nil
+}
+
+func (r *UploadWithSecretRequest) ToURLRequest(secretId string) *azBlobModel.UploadURLRequest {
+	return &azBlobModel.UploadURLRequest{
+		SessionId: r.SessionId,
+		SecretId:  secretId,
+		URL:       r.URL,
+	}
 }
 
 func (r *UploadWithSecretRequest) ToBlobRequest(secretId string) *azBlobModel.UploadBlobRequest {
@@ -100,20 +199,25 @@ func (r *UploadWithSecretRequest) ToBlobRequest(secretId string) *azBlobModel.Up
 }
 
 type UploadChunkWithSecretRequest struct {
-	SessionId   string     `json:"session_id"`
-	Secret      string     `json:"secret"`
-	FileId      string     `json:"file_id"`
-	FileName    string     `json:"file_name"`
-	Chunk       xtype.File `json:"chunk"`
-	ChunkIndex  int64      `json:"chunk_index"`
-	TotalChunks int64      `json:"total_chunks"`
+	SessionId         string     `json:"session_id"`
+	Secret            string     `json:"secret"`
+	FileId            string     `json:"file_id"`
+	FileName          string     `json:"file_name"`
+	Chunk             xtype.File `json:"chunk"`
+	ChunkIndex        int64      `json:"chunk_index"`
+	TotalChunks       int64      `json:"total_chunks"`
+	LifeTime          int64      `json:"life_time"`
+	ExpireAt          time.Time  `json:"expire_at"`
+	ChecksumAlgorithm string     `json:"checksum_algorithm"`
+	Checksum          string     `json:"checksum"`
 }
 
-func (r *UploadChunkWithSecretRequest) ToBlobRequest(secretId, token string) *azBlobModel.UploadChunkRequest {
+func (r *UploadChunkWithSecretRequest) ToBlobRequest(secretId, token, uploadId string) *azBlobModel.UploadChunkRequest {
 	return &azBlobModel.UploadChunkRequest{
 		SessionId:   r.SessionId,
 		SecretId:    secretId,
 		Token:       token,
+		UploadId:    uploadId,
 		FileName:    r.FileName,
 		Chunk:       r.Chunk,
 		ChunkIndex:  r.ChunkIndex,
@@ -122,12 +226,15 @@ func (r *UploadChunkWithSecretRequest) ToBlobRequest(secretId, token string) *az
 }
 
 type UploadResponse struct {
-	Url      string `json:"url"`
-	Token    string `json:"token"`
-	Ext      string `json:"ext"`
-	FileId   string `json:"file_id"`
-	FileName string `json:"file_name"`
-	FileSize int64  `json:"file_size"`
+	Url        string     `json:"url"`
+	Token      string     `json:"token"`
+	Ext        string     `json:"ext"`
+	FileId     string     `json:"file_id"`
+	FileName   string     `json:"file_name"`
+	FileSize   int64      `json:"file_size"`
+	Sha256     string     `json:"sha256"`
+	ScanStatus string     `json:"scan_status"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
 }
 
 type DownloadRequest struct {
@@ -138,9 +245,10 @@ type DownloadRequest struct {
 }
 
 type RequestDownloadRequest struct {
-	FileId string `json:"file_id"`
-	Secret string `json:"secret"`
-	Token  string `json:"token"`
+	FileId  string `json:"file_id"`
+	Secret  string `json:"secret"`
+	PinCode string `json:"pin_code"`
+	Token   string `json:"token"`
 }
 
 type RequestDownloadResponse struct {
@@ -149,6 +257,53 @@ type RequestDownloadResponse struct {
 	FileName string `json:"file_name"`
 }
 
+type DeleteRequest struct {
+	FileId string `json:"file_id"`
+	Token  string `json:"token"`
+}
+
+type DeleteWithSecretRequest struct {
+	FileId  string `json:"file_id"`
+	Secret  string `json:"secret"`
+	PinCode string `json:"pin_code"`
+}
+
+type DeleteResponse struct {
+	FileId string `json:"file_id"`
+}
+
+type RestoreWithSecretRequest struct {
+	FileId string `json:"file_id"`
+	Secret string `json:"secret"`
+}
+
+type RestoreResponse struct {
+	FileId string `json:"file_id"`
+}
+
+type PendingUploadRequest struct {
+	Secret string `json:"secret"`
+}
+
+type PendingUploadResponse struct {
+	Pending    int64 `json:"pending"`
+	Abandoned  int64 `json:"abandoned"`
+	PendingTTL int64 `json:"pending_ttl"`
+}
+
+type StorageStatsRequest struct {
+	Secret string `json:"secret"`
+}
+
+type StorageStatsResponse struct {
+	Secrets      int64 `json:"secrets"`
+	Files        int64 `json:"files"`
+	Blobs        int64 `json:"blobs"`
+	LogicalSize  int64 `json:"logical_size"`
+	PhysicalSize int64 `json:"physical_size"`
+	SavedSize    int64 `json:"saved_size"`
+}
+
 type DownloadResponse struct {
 	Url string `json:"url"`
 }
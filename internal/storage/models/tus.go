@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrTusNotFound          = errors.New("upload not found")
+	ErrTusOffsetMismatch    = errors.New("upload offset mismatch")
+	ErrTusChecksumMismatch  = errors.New("upload checksum mismatch")
+	ErrTusUploadCompleted   = errors.New("upload already completed")
+	ErrTusInvalidChecksum   = errors.New("upload checksum is invalid")
+	ErrTusUnsupportedLength = errors.New("upload length must be greater than 0")
+	ErrTusTooLarge          = errors.New("upload length exceeds the max size")
+)
+
+type TusCreateRequest struct {
+	UploadLength int64
+	FileName     string
+	FileType     string
+	Secret       string
+	LifeTime     int64
+	ExpireAt     time.Time
+}
+
+type TusCreateResponse struct {
+	FileId       string
+	Token        string
+	UploadOffset int64
+}
+
+// follow-up requests must come from the creator of the upload, with the token of its
+// location and the secret of a private upload
+
+type TusHeadRequest struct {
+	FileId string
+	Token  string
+	Secret string
+}
+
+type TusHeadResponse struct {
+	UploadOffset int64
+	UploadLength int64
+}
+
+type TusPatchRequest struct {
+	FileId            string
+	Token             string
+	Secret            string
+	UploadOffset      int64
+	ChecksumAlgorithm string
+	Checksum          []byte
+	MaxSize           int64
+	Body              io.Reader
+}
+
+type TusPatchResponse struct {
+	UploadOffset int64
+}
+
+type TusDeleteRequest struct {
+	FileId string
+	Token  string
+	Secret string
+}
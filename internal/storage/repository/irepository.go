@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"medioa/internal/storage/entity"
+	"medioa/internal/storage/models"
 )
 
 type IRepository interface {
@@ -15,4 +16,10 @@ type IRepository interface {
 	CreateMany(ctx context.Context, objs []*entity.Storage) ([]*entity.Storage, error)
 	Update(ctx context.Context, obj *entity.Storage) (*entity.Storage, error)
 	UpdateMany(ctx context.Context, objs []*entity.Storage) (int64, error)
+	Delete(ctx context.Context, obj *entity.Storage) (int64, error)
+	AddChunk(ctx context.Context, req *models.AddChunkRequest) (int64, error)
+	HashChunk(ctx context.Context, req *models.HashChunkRequest) (int64, error)
+	LockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error)
+	UnlockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error)
+	GetBlobStats(ctx context.Context, queries map[string]any) (*models.BlobStats, error)
 }
@@ -2,14 +2,22 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"medioa/config"
 	"medioa/constants"
 	"medioa/internal/storage/entity"
+	"medioa/internal/storage/models"
 	commonModel "medioa/models"
+	"medioa/pkg/xmongo"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/vukyn/kuery/conv"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	mongoo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongo struct {
@@ -31,19 +39,297 @@ func (m *mongo) withCollection() *mongoo.Collection {
 }
 
 func (m *mongo) GetById(ctx context.Context, id int64) (*entity.Storage, error) {
-	return nil, nil
+	queries := map[string]any{constants.FIELD_STORAGE_ID: id}
+	return m.GetOne(ctx, queries)
 }
 func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Storage, error) {
-	filter := make([]bson.E, 0)
+	filter := m.filter(queries)
+
+	var obj entity.Storage
+	err := m.withCollection().FindOne(ctx, filter).Decode(&obj)
+	if err == mongoo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+func (m *mongo) GetList(ctx context.Context, queries map[string]any) ([]*entity.Storage, error) {
+	filter := m.filter(queries)
+	opts := options.Find().SetSort(m.sort(queries))
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.Storage{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+func (m *mongo) GetListPaging(ctx context.Context, queries map[string]any) ([]*entity.Storage, error) {
+	filter := m.filter(queries)
+	page := conv.ReadInterface(queries, constants.FIELD_PAGE, constants.DEFAULT_PAGE)
+	size := conv.ReadInterface(queries, constants.FIELD_SIZE, constants.DEFAULT_SIZE)
+	opts := xmongo.Paging(page, size).SetSort(m.sort(queries))
+
+	cursor, err := m.withCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*entity.Storage{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+func (m *mongo) Count(ctx context.Context, queries map[string]any) (int64, error) {
+	filter := m.filter(queries)
+	count, err := m.withCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+func (m *mongo) Create(ctx context.Context, obj *entity.Storage) (*entity.Storage, error) {
+	_, err := m.withCollection().InsertOne(ctx, obj.ToBson())
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+func (m *mongo) CreateMany(ctx context.Context, objs []*entity.Storage) ([]*entity.Storage, error) {
+	if len(objs) == 0 {
+		return objs, nil
+	}
+	docs := make([]any, 0, len(objs))
+	for _, obj := range objs {
+		docs = append(docs, obj.ToBson())
+	}
+	if _, err := m.withCollection().InsertMany(ctx, docs); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+func (m *mongo) Update(ctx context.Context, obj *entity.Storage) (*entity.Storage, error) {
+	_, err := m.withCollection().UpdateOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}}, bson.D{{Key: "$set", Value: obj.ToBson()}})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+func (m *mongo) UpdateMany(ctx context.Context, objs []*entity.Storage) (int64, error) {
+	if len(objs) == 0 {
+		return 0, nil
+	}
+	writes := make([]mongoo.WriteModel, 0, len(objs))
+	for _, obj := range objs {
+		writes = append(writes, mongoo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: obj.UUID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: obj.ToBson()}}))
+	}
+	res, err := m.withCollection().BulkWrite(ctx, writes)
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+func (m *mongo) Delete(ctx context.Context, obj *entity.Storage) (int64, error) {
+	res, err := m.withCollection().DeleteOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+func (m *mongo) AddChunk(ctx context.Context, req *models.AddChunkRequest) (int64, error) {
+	// chunk_ids is positional, mongo pads the gap with null when the index is out of range
+	chunkKey := fmt.Sprintf("chunk_ids.%d", req.ChunkIndex)
+	uncommitted := bson.E{Key: "file_size", Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}}
+
+	set := bson.D{{Key: chunkKey, Value: req.ChunkId}}
+	if req.Type != "" {
+		set = append(set, bson.E{Key: "type", Value: req.Type})
+	}
+
+	// first arrival of this index also counts its size
+	res, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: req.Id},
+		uncommitted,
+		{Key: chunkKey, Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}},
+	}, bson.D{
+		{Key: "$set", Value: append(set, bson.E{Key: "total_chunks", Value: req.TotalChunks})},
+		{Key: "$inc", Value: bson.D{
+			{Key: "uploaded_size", Value: req.ChunkSize},
+			{Key: constants.FIELD_STORAGE_CHUNK_WRITES, Value: 1},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if res.MatchedCount == 0 {
+		// retransmitted chunk only replaces the block id
+		res, err = m.withCollection().UpdateOne(ctx, bson.D{
+			{Key: "_id", Value: req.Id},
+			uncommitted,
+		}, bson.D{
+			{Key: "$set", Value: set},
+			{Key: "$inc", Value: bson.D{{Key: constants.FIELD_STORAGE_CHUNK_WRITES, Value: 1}}},
+		})
+		if err != nil {
+			return 0, err
+		}
+		if res.MatchedCount == 0 {
+			return 0, nil
+		}
+	}
+
+	// a hashed chunk was staged again, its content may differ from the hashed one
+	if _, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: req.Id},
+		{Key: constants.FIELD_STORAGE_HASHED_CHUNKS, Value: bson.D{{Key: "$gt", Value: req.ChunkIndex}}},
+	}, bson.D{
+		{Key: "$unset", Value: bson.D{
+			{Key: constants.FIELD_STORAGE_HASH_STATE, Value: ""},
+			{Key: constants.FIELD_STORAGE_HASHED_SIZE, Value: ""},
+			{Key: constants.FIELD_STORAGE_HASHED_CHUNKS, Value: ""},
+		}},
+	}); err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+// HashChunk folds the next chunk into the running checksum, unless another chunk was staged since
+func (m *mongo) HashChunk(ctx context.Context, req *models.HashChunkRequest) (int64, error) {
+	res, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: req.Id},
+		{Key: "file_size", Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}},
+		zeroOrEqual(constants.FIELD_STORAGE_HASHED_CHUNKS, req.ChunkIndex),
+		zeroOrEqual(constants.FIELD_STORAGE_CHUNK_WRITES, req.ChunkWrites),
+	}, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: constants.FIELD_STORAGE_HASH_STATE, Value: req.HashState},
+			{Key: constants.FIELD_STORAGE_HASHED_CHUNKS, Value: req.ChunkIndex + 1},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: constants.FIELD_STORAGE_HASHED_SIZE, Value: req.ChunkSize}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+// LockUpload leases the upload while it is still at the offset and no other lease is alive
+func (m *mongo) LockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error) {
+	res, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: constants.FIELD_STORAGE_UUID, Value: req.Id},
+		zeroOrEqual(constants.FIELD_STORAGE_UPLOAD_OFFSET, req.UploadOffset),
+		{Key: constants.FIELD_STORAGE_PATCH_LOCKED_UNTIL, Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gt", Value: time.Now()}}}}},
+	}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: constants.FIELD_STORAGE_PATCH_LOCKED_UNTIL, Value: req.LockedUntil}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+// UnlockUpload releases the lease, unless it expired and was taken by another patch
+func (m *mongo) UnlockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error) {
+	res, err := m.withCollection().UpdateOne(ctx, bson.D{
+		{Key: constants.FIELD_STORAGE_UUID, Value: req.Id},
+		{Key: constants.FIELD_STORAGE_PATCH_LOCKED_UNTIL, Value: req.LockedUntil},
+	}, bson.D{
+		{Key: "$unset", Value: bson.D{{Key: constants.FIELD_STORAGE_PATCH_LOCKED_UNTIL, Value: ""}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+func (m *mongo) GetBlobStats(ctx context.Context, queries map[string]any) (*models.BlobStats, error) {
+	filter := m.filter(queries)
+	pipeline := mongoo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		// one group per stored blob, files without blob name own their blob
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$blob_name", "$_id"}}}},
+			{Key: "files", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "logical_size", Value: bson.D{{Key: "$sum", Value: "$file_size"}}},
+			{Key: "physical_size", Value: bson.D{{Key: "$max", Value: "$file_size"}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "files", Value: bson.D{{Key: "$sum", Value: "$files"}}},
+			{Key: "blobs", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "logical_size", Value: bson.D{{Key: "$sum", Value: "$logical_size"}}},
+			{Key: "physical_size", Value: bson.D{{Key: "$sum", Value: "$physical_size"}}},
+		}}},
+	}
+
+	cursor, err := m.withCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*models.BlobStats{}
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+	if len(objs) == 0 {
+		return &models.BlobStats{}, nil
+	}
+	return objs[0], nil
+}
+
+func (m *mongo) sort(queries map[string]any) bson.D {
+	sortMultiple := conv.ReadInterface(queries, constants.FIELD_SORT_MULTIPLE, "")
+	sortBy := conv.ReadInterface(queries, constants.FIELD_SORT_BY, "")
+	orderBy := conv.ReadInterface(queries, constants.FIELD_ORDER_BY, constants.DEFAULT_SORT_ORDER)
+	allowed := map[string]string{
+		constants.FIELD_STORAGE_ID:           "id",
+		constants.FIELD_STORAGE_TYPE:         "type",
+		constants.FIELD_STORAGE_TOKEN:        "token",
+		constants.FIELD_STORAGE_LIFE_TIME:    "life_time",
+		constants.FIELD_STORAGE_FILE_NAME:    "file_name",
+		constants.FIELD_STORAGE_FILE_SIZE:    "file_size",
+		constants.FIELD_STORAGE_EXT:          "ext",
+		constants.FIELD_STORAGE_DOWNLOAD_URL: "download_url",
+		constants.FIELD_STORAGE_CREATED_BY:   "created_by",
+		constants.FIELD_STORAGE_CREATED_AT:   "created_at",
+		constants.FIELD_STORAGE_DELETED_AT:   "deleted_at",
+		constants.FIELD_STORAGE_EXPIRED_AT:   "expired_at",
+	}
+	return xmongo.Sort(sortMultiple, sortBy, orderBy, allowed, bson.D{{Key: "created_at", Value: -1}})
+}
+
+func (m *mongo) filter(queries map[string]any) bson.D {
+	filter := make(bson.D, 0)
+	id := conv.ReadInterface(queries, constants.FIELD_STORAGE_ID, int64(0))
 	uuid := conv.ReadInterface(queries, constants.FIELD_STORAGE_UUID, "")
 	downloadUrl := conv.ReadInterface(queries, constants.FIELD_STORAGE_DOWNLOAD_URL, "")
 	downloadPassword := conv.ReadInterface(queries, constants.FIELD_STORAGE_DOWNLOAD_PASSWORD, "")
 	_type := conv.ReadInterface(queries, constants.FIELD_STORAGE_TYPE, "")
 	token := conv.ReadInterface(queries, constants.FIELD_STORAGE_TOKEN, "")
 	ext := conv.ReadInterface(queries, constants.FIELD_STORAGE_EXT, "")
+	fileName := conv.ReadInterface(queries, constants.FIELD_STORAGE_FILE_NAME, "")
 	secretId := conv.ReadInterface(queries, constants.FIELD_STORAGE_SECRET_ID, "")
-	lifeTime := conv.ReadInterface(queries, constants.FIELD_STORAGE_LIFE_TIME, 0)
+	lifeTime := conv.ReadInterface(queries, constants.FIELD_STORAGE_LIFE_TIME, int64(0))
+	createdBy := conv.ReadInterface(queries, constants.FIELD_STORAGE_CREATED_BY, int64(0))
+	createdFrom := conv.ReadInterface(queries, constants.FIELD_STORAGE_CREATED_FROM, time.Time{})
+	createdTo := conv.ReadInterface(queries, constants.FIELD_STORAGE_CREATED_TO, time.Time{})
+	isDeleted := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_DELETED, (*bool)(nil))
+	deletedBefore := conv.ReadInterface(queries, constants.FIELD_STORAGE_DELETED_BEFORE, time.Time{})
+	expiredBefore := conv.ReadInterface(queries, constants.FIELD_STORAGE_EXPIRED_BEFORE, time.Time{})
+	isPending := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_PENDING, (*bool)(nil))
+	isReleasing := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_RELEASING, (*bool)(nil))
+	sha256 := conv.ReadInterface(queries, constants.FIELD_STORAGE_SHA256, "")
+	blobName := conv.ReadInterface(queries, constants.FIELD_STORAGE_BLOB_NAME, "")
+	hasSecret := conv.ReadInterface(queries, constants.FIELD_STORAGE_HAS_SECRET, (*bool)(nil))
 
+	if id != 0 {
+		filter = append(filter, bson.E{Key: "id", Value: id})
+	}
 	if uuid != "" {
 		filter = append(filter, bson.E{Key: "_id", Value: uuid})
 	}
@@ -54,7 +340,12 @@ func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Sto
 		filter = append(filter, bson.E{Key: "download_password", Value: downloadPassword})
 	}
 	if _type != "" {
-		filter = append(filter, bson.E{Key: "type", Value: _type})
+		if strings.Contains(_type, "/") {
+			filter = append(filter, bson.E{Key: "type", Value: _type})
+		} else {
+			// mime family, e.g. "image" matches "image/png"
+			filter = append(filter, bson.E{Key: "type", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(_type) + "/"}})
+		}
 	}
 	if token != "" {
 		filter = append(filter, bson.E{Key: "token", Value: token})
@@ -65,45 +356,86 @@ func (m *mongo) GetOne(ctx context.Context, queries map[string]any) (*entity.Sto
 	if ext != "" {
 		filter = append(filter, bson.E{Key: "ext", Value: ext})
 	}
+	if fileName != "" {
+		filter = append(filter, bson.E{Key: "file_name", Value: primitive.Regex{Pattern: regexp.QuoteMeta(fileName), Options: "i"}})
+	}
 	if secretId != "" {
 		filter = append(filter, bson.E{Key: "secret_id", Value: secretId})
 	}
-
-	var obj entity.Storage
-	err := m.withCollection().FindOne(ctx, filter).Decode(&obj)
-	if err == mongoo.ErrNoDocuments {
-		return nil, nil
-	} else if err != nil {
-		return nil, err
+	if createdBy != 0 {
+		filter = append(filter, bson.E{Key: "created_by", Value: createdBy})
 	}
-	return &obj, nil
-}
-func (m *mongo) GetList(ctx context.Context, queries map[string]any) ([]*entity.Storage, error) {
-	return nil, nil
-}
-func (m *mongo) GetListPaging(ctx context.Context, queries map[string]any) ([]*entity.Storage, error) {
-	return nil, nil
-}
-func (m *mongo) Count(ctx context.Context, queries map[string]any) (int64, error) {
-	return 0, nil
-}
-func (m *mongo) Create(ctx context.Context, obj *entity.Storage) (*entity.Storage, error) {
-	_, err := m.withCollection().InsertOne(ctx, obj.ToBson())
-	if err != nil {
-		return nil, err
+	if !createdFrom.IsZero() || !createdTo.IsZero() {
+		createdAt := bson.D{}
+		if !createdFrom.IsZero() {
+			createdAt = append(createdAt, bson.E{Key: "$gte", Value: createdFrom.UnixMilli()})
+		}
+		if !createdTo.IsZero() {
+			createdAt = append(createdAt, bson.E{Key: "$lte", Value: createdTo.UnixMilli()})
+		}
+		filter = append(filter, bson.E{Key: "created_at", Value: createdAt})
 	}
-	return obj, nil
-}
-func (m *mongo) CreateMany(ctx context.Context, objs []*entity.Storage) ([]*entity.Storage, error) {
-	return nil, nil
-}
-func (m *mongo) Update(ctx context.Context, obj *entity.Storage) (*entity.Storage, error) {
-	_, err := m.withCollection().UpdateOne(ctx, bson.D{{Key: "_id", Value: obj.UUID}}, bson.D{{Key: "$set", Value: obj.ToBson()}})
-	if err != nil {
-		return nil, err
+	if isDeleted != nil {
+		if *isDeleted {
+			filter = append(filter, bson.E{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}})
+			// a file being released is gone, even from trash
+			if isReleasing == nil {
+				filter = append(filter, bson.E{Key: "is_releasing", Value: bson.D{{Key: "$ne", Value: true}}})
+			}
+		} else {
+			// matches both missing and cleared deleted_at
+			filter = append(filter, bson.E{Key: "deleted_at", Value: nil})
+		}
 	}
-	return obj, nil
+	if !deletedBefore.IsZero() {
+		filter = append(filter, bson.E{Key: "deleted_at", Value: bson.D{{Key: "$lte", Value: deletedBefore.UnixMilli()}}})
+	}
+	if !expiredBefore.IsZero() {
+		// expired_at is only written when the file has a lifetime
+		filter = append(filter, bson.E{Key: "expired_at", Value: bson.D{{Key: "$lte", Value: expiredBefore.UnixMilli()}}})
+	}
+	if isPending != nil {
+		// chunk_ids is emptied on commit, so a pending upload still has staged chunks,
+		// uploads reserved before any content are marked explicitly
+		if *isPending {
+			filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+				bson.D{{Key: "is_pending", Value: true}},
+				bson.D{{Key: "chunk_ids.0", Value: bson.D{{Key: "$exists", Value: true}}}},
+			}})
+		} else {
+			filter = append(filter,
+				bson.E{Key: "is_pending", Value: bson.D{{Key: "$ne", Value: true}}},
+				bson.E{Key: "chunk_ids.0", Value: bson.D{{Key: "$exists", Value: false}}},
+			)
+		}
+	}
+	if isReleasing != nil {
+		if *isReleasing {
+			filter = append(filter, bson.E{Key: "is_releasing", Value: true})
+		} else {
+			filter = append(filter, bson.E{Key: "is_releasing", Value: bson.D{{Key: "$ne", Value: true}}})
+		}
+	}
+	if sha256 != "" {
+		filter = append(filter, bson.E{Key: "sha256", Value: sha256})
+	}
+	if blobName != "" {
+		filter = append(filter, bson.E{Key: "blob_name", Value: blobName})
+	}
+	if hasSecret != nil {
+		if *hasSecret {
+			filter = append(filter, bson.E{Key: "secret_id", Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}})
+		} else {
+			filter = append(filter, bson.E{Key: "secret_id", Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}})
+		}
+	}
+	return filter
 }
-func (m *mongo) UpdateMany(ctx context.Context, objs []*entity.Storage) (int64, error) {
-	return 0, nil
+
+// zeroOrEqual matches a number, a zero is matched by a missing field as zeros are not stored
+func zeroOrEqual(key string, value int64) bson.E {
+	if value == 0 {
+		return bson.E{Key: key, Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}}
+	}
+	return bson.E{Key: key, Value: value}
 }
@@ -12,10 +12,13 @@ import (
 	"fmt"
 	"medioa/constants"
 	"medioa/internal/storage/entity"
+	"medioa/internal/storage/models"
 	commonModel "medioa/models"
+	"time"
 
 	"github.com/vukyn/kuery/conv"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repo struct {
@@ -58,6 +61,94 @@ func (r *repo) Update(ctx context.Context, obj *entity.Storage) (*entity.Storage
 	return obj, nil
 }
 
+func (r *repo) AddChunk(ctx context.Context, req *models.AddChunkRequest) (int64, error) {
+	var affected int64
+	err := r.dbWithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock the row so parallel chunks of the same file are serialized
+		obj := &entity.Storage{}
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(r.tableName+".uuid = ? AND "+r.tableName+".file_size = 0", req.Id).
+			Limit(1).Find(obj)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		chunkIds := make([]string, 0)
+		if obj.ChunkIds != nil {
+			chunkIds = *obj.ChunkIds
+		}
+		for int64(len(chunkIds)) <= req.ChunkIndex {
+			chunkIds = append(chunkIds, "")
+		}
+		if chunkIds[req.ChunkIndex] == "" {
+			obj.UploadedSize += req.ChunkSize
+		}
+		chunkIds[req.ChunkIndex] = req.ChunkId
+		obj.ChunkIds = &chunkIds
+		obj.TotalChunks = req.TotalChunks
+		obj.ChunkWrites++
+		if req.Type != "" {
+			obj.Type = req.Type
+		}
+
+		result = tx.Updates(obj)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		// a hashed chunk was staged again, its content may differ from the hashed one
+		if obj.HashedChunks > req.ChunkIndex {
+			return tx.Model(obj).Updates(map[string]any{
+				"hash_state":    nil,
+				"hashed_size":   0,
+				"hashed_chunks": 0,
+			}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+func (r *repo) HashChunk(ctx context.Context, req *models.HashChunkRequest) (int64, error) {
+	result := r.dbWithContext(ctx).Model(&entity.Storage{}).
+		Where(r.tableName+".uuid = ? AND "+r.tableName+".file_size = 0", req.Id).
+		Where(r.tableName+".hashed_chunks = ? AND "+r.tableName+".chunk_writes = ?", req.ChunkIndex, req.ChunkWrites).
+		Updates(map[string]any{
+			"hash_state":    req.HashState,
+			"hashed_chunks": req.ChunkIndex + 1,
+			"hashed_size":   gorm.Expr("hashed_size + ?", req.ChunkSize),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *repo) LockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error) {
+	result := r.dbWithContext(ctx).Model(&entity.Storage{}).
+		Where(r.tableName+".uuid = ? AND "+r.tableName+".upload_offset = ?", req.Id, req.UploadOffset).
+		Where("("+r.tableName+".patch_locked_until IS NULL OR "+r.tableName+".patch_locked_until <= ?)", time.Now()).
+		Update("patch_locked_until", req.LockedUntil)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *repo) UnlockUpload(ctx context.Context, req *models.LockUploadRequest) (int64, error) {
+	result := r.dbWithContext(ctx).Model(&entity.Storage{}).
+		Where(r.tableName+".uuid = ? AND "+r.tableName+".patch_locked_until = ?", req.Id, req.LockedUntil).
+		Update("patch_locked_until", nil)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *repo) UpdateMany(ctx context.Context, objs []*entity.Storage) (int64, error) {
 	tx := r.dbWithContext(ctx).Begin()
 	defer func() {
@@ -77,6 +168,14 @@ func (r *repo) UpdateMany(ctx context.Context, objs []*entity.Storage) (int64, e
 	return int64(len(objs)), nil
 }
 
+func (r *repo) Delete(ctx context.Context, obj *entity.Storage) (int64, error) {
+	result := r.dbWithContext(ctx).Delete(obj)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *repo) initQuery(ctx context.Context, queries map[string]any) *gorm.DB {
 	obj := &entity.Storage{}
 	query := r.dbWithContext(ctx).Model(obj)
@@ -132,6 +231,28 @@ func (r *repo) Count(ctx context.Context, queries map[string]any) (int64, error)
 	return count, nil
 }
 
+func (r *repo) GetBlobStats(ctx context.Context, queries map[string]any) (*models.BlobStats, error) {
+	// one row per stored blob, files without blob name own their blob
+	subQuery := r.dbWithContext(ctx).Model(&entity.Storage{}).Select(
+		"COUNT(1) AS files",
+		"SUM("+r.tableName+"."+constants.FIELD_STORAGE_FILE_SIZE+") AS logical_size",
+		"MAX("+r.tableName+"."+constants.FIELD_STORAGE_FILE_SIZE+") AS physical_size",
+	)
+	subQuery = r.filter(subQuery, queries)
+	subQuery = subQuery.Group("COALESCE(NULLIF(" + r.tableName + "." + constants.FIELD_STORAGE_BLOB_NAME + ", ''), " + r.tableName + ".uuid)")
+
+	obj := &models.BlobStats{}
+	if err := r.dbWithContext(ctx).Table("(?) AS tmp", subQuery).Select(
+		"COALESCE(SUM(tmp.files), 0) AS files",
+		"COUNT(1) AS blobs",
+		"COALESCE(SUM(tmp.logical_size), 0) AS logical_size",
+		"COALESCE(SUM(tmp.physical_size), 0) AS physical_size",
+	).Scan(obj).Error; err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
 func (r *repo) GetById(ctx context.Context, id int64) (*entity.Storage, error) {
 	queries := map[string]any{constants.FIELD_STORAGE_ID: id}
 	return r.GetOne(ctx, queries)
@@ -213,12 +334,21 @@ func (r *repo) sort(query *gorm.DB, queries map[string]any) *gorm.DB {
 }
 
 func (r *repo) filter(query *gorm.DB, queries map[string]any) *gorm.DB {
-	id := conv.ReadInterface(queries, constants.FIELD_STORAGE_ID, 0)
+	id := conv.ReadInterface(queries, constants.FIELD_STORAGE_ID, int64(0))
 	downloadUrl := conv.ReadInterface(queries, constants.FIELD_STORAGE_DOWNLOAD_URL, "")
 	_type := conv.ReadInterface(queries, constants.FIELD_STORAGE_TYPE, "")
 	token := conv.ReadInterface(queries, constants.FIELD_STORAGE_TOKEN, "")
-	lifeTime := conv.ReadInterface(queries, constants.FIELD_STORAGE_LIFE_TIME, 0)
-	createdBy := conv.ReadInterface(queries, constants.FIELD_STORAGE_CREATED_BY, 0)
+	lifeTime := conv.ReadInterface(queries, constants.FIELD_STORAGE_LIFE_TIME, int64(0))
+	createdBy := conv.ReadInterface(queries, constants.FIELD_STORAGE_CREATED_BY, int64(0))
+	isDeleted := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_DELETED, (*bool)(nil))
+	deletedBefore := conv.ReadInterface(queries, constants.FIELD_STORAGE_DELETED_BEFORE, time.Time{})
+	expiredBefore := conv.ReadInterface(queries, constants.FIELD_STORAGE_EXPIRED_BEFORE, time.Time{})
+	isPending := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_PENDING, (*bool)(nil))
+	isReleasing := conv.ReadInterface(queries, constants.FIELD_STORAGE_IS_RELEASING, (*bool)(nil))
+	secretId := conv.ReadInterface(queries, constants.FIELD_STORAGE_SECRET_ID, "")
+	sha256 := conv.ReadInterface(queries, constants.FIELD_STORAGE_SHA256, "")
+	blobName := conv.ReadInterface(queries, constants.FIELD_STORAGE_BLOB_NAME, "")
+	hasSecret := conv.ReadInterface(queries, constants.FIELD_STORAGE_HAS_SECRET, (*bool)(nil))
 
 	if id != 0 {
 		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_ID+" = ? ", id)
@@ -238,5 +368,53 @@ func (r *repo) filter(query *gorm.DB, queries map[string]any) *gorm.DB {
 	if createdBy != 0 {
 		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_CREATED_BY+" = ? ", createdBy)
 	}
+	if isDeleted != nil {
+		if *isDeleted {
+			query = query.Where(r.tableName + "." + constants.FIELD_STORAGE_DELETED_AT + " IS NOT NULL ")
+			// a file being released is gone, even from trash
+			if isReleasing == nil {
+				query = query.Where(r.tableName + ".is_releasing IS NOT TRUE ")
+			}
+		} else {
+			query = query.Where(r.tableName + "." + constants.FIELD_STORAGE_DELETED_AT + " IS NULL ")
+		}
+	}
+	if !deletedBefore.IsZero() {
+		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_DELETED_AT+" <= ? ", deletedBefore)
+	}
+	if !expiredBefore.IsZero() {
+		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_EXPIRED_AT+" <= ? ", expiredBefore)
+	}
+	if isPending != nil {
+		pending := "(" + r.tableName + ".is_pending = TRUE OR (" + r.tableName + ".file_size = 0 AND " + r.tableName + ".chunk_ids IS NOT NULL)) "
+		if *isPending {
+			query = query.Where(pending)
+		} else {
+			query = query.Where("NOT " + pending)
+		}
+	}
+	if isReleasing != nil {
+		if *isReleasing {
+			query = query.Where(r.tableName + ".is_releasing IS TRUE ")
+		} else {
+			query = query.Where(r.tableName + ".is_releasing IS NOT TRUE ")
+		}
+	}
+	if secretId != "" {
+		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_SECRET_ID+" = ? ", secretId)
+	}
+	if sha256 != "" {
+		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_SHA256+" = ? ", sha256)
+	}
+	if blobName != "" {
+		query = query.Where(r.tableName+"."+constants.FIELD_STORAGE_BLOB_NAME+" = ? ", blobName)
+	}
+	if hasSecret != nil {
+		if *hasSecret {
+			query = query.Where("COALESCE(" + r.tableName + "." + constants.FIELD_STORAGE_SECRET_ID + ", '') <> '' ")
+		} else {
+			query = query.Where("COALESCE(" + r.tableName + "." + constants.FIELD_STORAGE_SECRET_ID + ", '') = '' ")
+		}
+	}
 	return query
 }
@@ -16,4 +16,10 @@ type IService interface {
 	Update(ctx context.Context, userId int64, params *models.SaveRequest) (*models.Response, error)
 	UpdateMany(ctx context.Context, userId int64, params []*models.SaveRequest) (int64, error)
 	Upsert(ctx context.Context, userId int64, params *models.SaveRequest) (*models.Response, error)
+	Delete(ctx context.Context, userId int64, params *models.SaveRequest) (int64, error)
+	AddChunk(ctx context.Context, userId int64, params *models.AddChunkRequest) (int64, error)
+	HashChunk(ctx context.Context, userId int64, params *models.HashChunkRequest) (int64, error)
+	LockUpload(ctx context.Context, userId int64, params *models.LockUploadRequest) (int64, error)
+	UnlockUpload(ctx context.Context, userId int64, params *models.LockUploadRequest) (int64, error)
+	GetBlobStats(ctx context.Context, params *models.RequestParams) (*models.BlobStats, error)
 }
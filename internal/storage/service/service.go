@@ -194,3 +194,65 @@ func (s *service) Upsert(ctx context.Context, userId int64, params *models.SaveR
 	}
 	return s.GetById(ctx, params.Id)
 }
+
+func (s *service) Delete(ctx context.Context, userId int64, params *models.SaveRequest) (int64, error) {
+	log := log.New("service", "Delete")
+	obj := &entity.Storage{}
+	obj.ParseFromSaveRequest(params)
+	res, err := s.repo.Delete(ctx, obj)
+	if err != nil {
+		log.Error("service.repo.Delete", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *service) AddChunk(ctx context.Context, userId int64, params *models.AddChunkRequest) (int64, error) {
+	log := log.New("service", "AddChunk")
+	res, err := s.repo.AddChunk(ctx, params)
+	if err != nil {
+		log.Error("service.repo.AddChunk", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *service) HashChunk(ctx context.Context, userId int64, params *models.HashChunkRequest) (int64, error) {
+	log := log.New("service", "HashChunk")
+	res, err := s.repo.HashChunk(ctx, params)
+	if err != nil {
+		log.Error("service.repo.HashChunk", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *service) LockUpload(ctx context.Context, userId int64, params *models.LockUploadRequest) (int64, error) {
+	log := log.New("service", "LockUpload")
+	res, err := s.repo.LockUpload(ctx, params)
+	if err != nil {
+		log.Error("service.repo.LockUpload", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *service) UnlockUpload(ctx context.Context, userId int64, params *models.LockUploadRequest) (int64, error) {
+	log := log.New("service", "UnlockUpload")
+	res, err := s.repo.UnlockUpload(ctx, params)
+	if err != nil {
+		log.Error("service.repo.UnlockUpload", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+func (s *service) GetBlobStats(ctx context.Context, params *models.RequestParams) (*models.BlobStats, error) {
+	log := log.New("service", "GetBlobStats")
+	res, err := s.repo.GetBlobStats(ctx, params.ToMap())
+	if err != nil {
+		log.Error("service.repo.GetBlobStats", err)
+		return nil, err
+	}
+	return res, nil
+}
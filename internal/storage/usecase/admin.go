@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"medioa/constants"
+	azBlobModel "medioa/internal/azblob/models"
+	lockoutModel "medioa/internal/lockout/models"
+	secretModel "medioa/internal/secret/models"
+	storageModel "medioa/internal/storage/models"
+	commonModel "medioa/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) ListSecrets(ctx context.Context, userId int64, params *storageModel.ListSecretsRequest) (*storageModel.ListSecretResponse, error) {
+	log := log.New("usecase", "ListSecrets")
+
+	// validation
+
+	// get master secret info
+	if _, err := u.verifyMasterSecret(ctx, params.Secret); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	secrets, err := u.secretSv.GetListPaging(ctx, &secretModel.RequestParams{
+		RequestParams: commonModel.RequestParams{
+			Page:    params.Page,
+			Size:    params.Size,
+			SortBy:  params.SortBy,
+			OrderBy: params.OrderBy,
+		},
+		ConfigQuery: constants.CONFIG_QUERY_GET_ALL,
+		Username:    params.Username,
+	})
+	if err != nil {
+		log.Error("usecase.secretSv.GetListPaging", err)
+		return nil, err
+	}
+
+	// never expose password or pin code hashes
+	records := make([]*storageModel.SecretResponse, 0, len(secrets.Records))
+	for _, secret := range secrets.Records {
+		records = append(records, &storageModel.SecretResponse{
+			UserId:    secret.UUID,
+			Username:  secret.Username,
+			Type:      secret.Type,
+			IsMaster:  secret.IsMaster,
+			CreatedAt: secret.CreatedAt,
+		})
+	}
+
+	return &storageModel.ListSecretResponse{
+		Page:    secrets.Page,
+		Size:    secrets.Size,
+		Count:   secrets.Count,
+		Records: records,
+	}, nil
+}
+
+func (u *usecase) ListFiles(ctx context.Context, userId int64, params *storageModel.ListFilesRequest) (*storageModel.ListFileResponse, error) {
+	log := log.New("usecase", "ListFiles")
+
+	// validation
+
+	// get master secret info
+	if _, err := u.verifyMasterSecret(ctx, params.Secret); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	isDeleted := params.Deleted
+	isPending := false
+	files, err := u.storageSv.GetListPaging(ctx, &storageModel.RequestParams{
+		RequestParams: commonModel.RequestParams{
+			Page:    params.Page,
+			Size:    params.Size,
+			SortBy:  params.SortBy,
+			OrderBy: params.OrderBy,
+		},
+		ConfigQuery: constants.CONFIG_QUERY_GET_ALL,
+		SecretId:    params.SecretId,
+		Type:        params.Type,
+		Ext:         params.Ext,
+		FileName:    params.FileName,
+		CreatedFrom: params.From,
+		CreatedTo:   params.To,
+		IsDeleted:   &isDeleted,
+		IsPending:   &isPending,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetListPaging", err)
+		return nil, err
+	}
+
+	records := make([]*storageModel.FileResponse, 0, len(files.Records))
+	for _, file := range files.Records {
+		records = append(records, &storageModel.FileResponse{
+			FileId:     file.UUID,
+			FileName:   file.FileName,
+			FileSize:   file.FileSize,
+			Type:       file.Type,
+			Ext:        file.Ext,
+			Token:      file.Token,
+			Url:        file.DownloadUrl,
+			SecretId:   file.SecretId,
+			CreatedAt:  file.CreatedAt,
+			Sha256:     file.Sha256,
+			ScanStatus: file.ScanStatus,
+			DeletedAt:  file.DeletedAt,
+			ExpiredAt:  timeOrNil(file.ExpiredAt),
+		})
+	}
+
+	return &storageModel.ListFileResponse{
+		Page:    files.Page,
+		Size:    files.Size,
+		Count:   files.Count,
+		Records: records,
+	}, nil
+}
+
+// AdminDownload reads any file without its link secret or download password
+func (u *usecase) AdminDownload(ctx context.Context, userId int64, params *storageModel.AdminFileRequest) (*storageModel.DownloadResponse, error) {
+	log := log.New("usecase", "AdminDownload")
+
+	// validation
+
+	// get master secret info, reading or deleting any file requires the pin code
+	if _, err := u.verifyMasterPin(ctx, params.Secret, params.PinCode); err != nil {
+		return nil, err
+	}
+
+	// get file info
+	file, err := u.getFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// refuse files not marked clean, even to the master
+	if err := u.verifyScanStatus(file); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	sas, err := u.azBlobSv.DownloadSAS(ctx, &azBlobModel.DownloadSASRequest{
+		FileName: getBlobName(file),
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.DownloadSAS", err)
+		return nil, err
+	}
+
+	return &storageModel.DownloadResponse{
+		Url: sas.Url,
+	}, nil
+}
+
+// AdminDelete moves any file to trash, its owner can still restore it until purged
+func (u *usecase) AdminDelete(ctx context.Context, userId int64, params *storageModel.AdminFileRequest) (*storageModel.DeleteResponse, error) {
+
+	// validation
+
+	// get master secret info, reading or deleting any file requires the pin code
+	if _, err := u.verifyMasterPin(ctx, params.Secret, params.PinCode); err != nil {
+		return nil, err
+	}
+
+	// get file info
+	file, err := u.getFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	return u.trashFile(ctx, userId, file)
+}
+
+// ResetSecretPassword sets a new password of another secret and signs out its sessions
+func (u *usecase) ResetSecretPassword(ctx context.Context, userId int64, params *storageModel.ResetSecretPasswordRequest) (int64, error) {
+	log := log.New("usecase", "ResetSecretPassword")
+
+	// validation
+
+	// get master secret info, resetting credentials of another secret requires the pin code
+	if _, err := u.verifyMasterPin(ctx, params.Secret, params.MasterPinCode); err != nil {
+		return 0, err
+	}
+
+	// check if password is valid
+	if params.Password == "" {
+		return 0, fmt.Errorf("password is required")
+	}
+
+	// check if secret exists, the master secret is only changed by its owner
+	secret, err := u.getSecretById(ctx, params.SecretId)
+	if err != nil {
+		return 0, err
+	}
+	if secret.IsMaster {
+		return 0, fmt.Errorf("master secret can not be reset")
+	}
+
+	// end validation
+
+	if _, err := u.secretSv.Update(ctx, userId, &secretModel.SaveRequest{
+		UUID:     secret.UUID,
+		Password: params.Password,
+	}); err != nil {
+		log.Error("usecase.secretSv.Update", err)
+		return 0, err
+	}
+
+	// the old password may be known to someone else
+	isRevoked := false
+	sessions, err := u.sessionSv.GetList(ctx, &secretModel.SessionRequestParams{
+		SecretId:  secret.UUID,
+		IsRevoked: &isRevoked,
+	})
+	if err != nil {
+		log.Error("usecase.sessionSv.GetList", err)
+		return 0, err
+	}
+	if _, err := u.revokeSessions(ctx, sessions); err != nil {
+		return 0, err
+	}
+	u.resetAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_LOGIN, secret.Username)
+
+	return 1, nil
+}
+
+// ResetSecretPinCode sets a new pin code of another secret, e.g. when its owner forgot it
+func (u *usecase) ResetSecretPinCode(ctx context.Context, userId int64, params *storageModel.ResetSecretPinCodeRequest) (int64, error) {
+	log := log.New("usecase", "ResetSecretPinCode")
+
+	// validation
+
+	// get master secret info, resetting credentials of another secret requires the pin code
+	if _, err := u.verifyMasterPin(ctx, params.Secret, params.MasterPinCode); err != nil {
+		return 0, err
+	}
+
+	// check if pin code is valid
+	if err := validatePinCode(params.PinCode); err != nil {
+		return 0, err
+	}
+
+	// check if secret exists, the master secret is only changed by its owner
+	secret, err := u.getSecretById(ctx, params.SecretId)
+	if err != nil {
+		return 0, err
+	}
+	if secret.IsMaster {
+		return 0, fmt.Errorf("master secret can not be reset")
+	}
+
+	// end validation
+
+	if _, err := u.secretSv.Update(ctx, userId, &secretModel.SaveRequest{
+		UUID:    secret.UUID,
+		PinCode: params.PinCode,
+	}); err != nil {
+		log.Error("usecase.secretSv.Update", err)
+		return 0, err
+	}
+	u.resetAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_PIN, secret.UUID)
+
+	return 1, nil
+}
+
+func (u *usecase) getSecretById(ctx context.Context, secretId string) (*secretModel.Response, error) {
+	log := log.New("usecase", "getSecretById")
+
+	if secretId == "" {
+		return nil, fmt.Errorf("secret id is required")
+	}
+
+	secret, err := u.secretSv.GetOne(ctx, &secretModel.RequestParams{
+		UUID: secretId,
+	})
+	if err != nil {
+		log.Error("usecase.secretSv.GetOne", err)
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("secret not found")
+	}
+
+	return secret, nil
+}
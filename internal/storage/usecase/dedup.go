@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	azBlobModel "medioa/internal/azblob/models"
+	secretModel "medioa/internal/secret/models"
+	storageModel "medioa/internal/storage/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) GetStorageStats(ctx context.Context, userId int64, params *storageModel.StorageStatsRequest) (*storageModel.StorageStatsResponse, error) {
+	log := log.New("usecase", "GetStorageStats")
+
+	// validation
+
+	// get master secret info
+	if _, err := u.verifyMasterSecret(ctx, params.Secret); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	isPending := false
+	stats, err := u.storageSv.GetBlobStats(ctx, &storageModel.RequestParams{
+		IsPending: &isPending,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetBlobStats", err)
+		return nil, err
+	}
+
+	secrets, err := u.secretSv.Count(ctx, &secretModel.RequestParams{})
+	if err != nil {
+		log.Error("usecase.secretSv.Count", err)
+		return nil, err
+	}
+
+	return &storageModel.StorageStatsResponse{
+		Secrets:      secrets,
+		Files:        stats.Files,
+		Blobs:        stats.Blobs,
+		LogicalSize:  stats.LogicalSize,
+		PhysicalSize: stats.PhysicalSize,
+		SavedSize:    stats.LogicalSize - stats.PhysicalSize,
+	}, nil
+}
+
+// dedupBlob returns the blob a new file should reference. When a file with the same content
+// exists in the same scope (public or the same secret), its blob is reused and the uploaded one is removed.
+// The file record must exist and not be committed yet, it references the reused blob before the uploaded
+// one is removed so a concurrent release of the reused blob counts it.
+func (u *usecase) dedupBlob(ctx context.Context, userId int64, fileId, blobName, sha256, secretId string) (string, error) {
+	log := log.New("usecase", "dedupBlob")
+
+	if sha256 == "" {
+		return blobName, nil
+	}
+
+	hasSecret := secretId != ""
+	isPending := false
+	isReleasing := false
+	dup, err := u.storageSv.GetOne(ctx, &storageModel.RequestParams{
+		Sha256:      sha256,
+		SecretId:    secretId,
+		HasSecret:   &hasSecret,
+		IsPending:   &isPending,
+		IsReleasing: &isReleasing,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetOne", err)
+		return "", err
+	}
+	if dup == nil {
+		return blobName, nil
+	}
+	dupBlobName := getBlobName(dup)
+	if dupBlobName == blobName {
+		return blobName, nil
+	}
+
+	// files stored before dedup have no blob name, set it so they are counted as a reference
+	if dup.BlobName == "" {
+		if _, err := u.storageSv.Update(ctx, dup.CreatedBy, &storageModel.SaveRequest{
+			UUID:     dup.UUID,
+			BlobName: dupBlobName,
+		}); err != nil {
+			log.Error("usecase.storageSv.Update", err)
+			return "", err
+		}
+	}
+
+	// reference the reused blob first
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:     fileId,
+		BlobName: dupBlobName,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return "", err
+	}
+
+	// the duplicate may have been released before it was referenced, keep the uploaded blob then
+	dup, err = u.storageSv.GetOne(ctx, &storageModel.RequestParams{
+		UUID:        dup.UUID,
+		IsReleasing: &isReleasing,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetOne", err)
+		return "", err
+	}
+	if dup == nil {
+		if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+			UUID:     fileId,
+			BlobName: blobName,
+		}); err != nil {
+			log.Error("usecase.storageSv.Update", err)
+			return "", err
+		}
+		return blobName, nil
+	}
+
+	// the uploaded blob is no longer referenced, it is only leaked if it can't be removed
+	if err := u.azBlobSv.DeleteBlob(ctx, &azBlobModel.DeleteBlobRequest{
+		FileName: blobName,
+	}); err != nil {
+		log.Error("usecase.azBlobSv.DeleteBlob", err)
+	}
+
+	return dupBlobName, nil
+}
+
+// releaseBlob deletes a blob once no file references it anymore
+func (u *usecase) releaseBlob(ctx context.Context, blobName string) error {
+	log := log.New("usecase", "releaseBlob")
+
+	isReleasing := false
+	refs, err := u.storageSv.Count(ctx, &storageModel.RequestParams{
+		BlobName:    blobName,
+		IsReleasing: &isReleasing,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.Count", err)
+		return err
+	}
+	if refs > 0 {
+		return nil
+	}
+
+	if err := u.azBlobSv.DeleteBlob(ctx, &azBlobModel.DeleteBlobRequest{
+		FileName: blobName,
+	}); err != nil {
+		log.Error("usecase.azBlobSv.DeleteBlob", err)
+		return err
+	}
+
+	return nil
+}
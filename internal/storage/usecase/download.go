@@ -2,10 +2,11 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	azBlobModel "medioa/internal/azblob/models"
+	lockoutModel "medioa/internal/lockout/models"
 	storageModel "medioa/internal/storage/models"
-	"path"
 
 	"github.com/vukyn/kuery/log"
 )
@@ -21,64 +22,57 @@ func (u *usecase) Download(ctx context.Context, userId int64, params *storageMod
 		return nil, err
 	}
 
-	// check permission
+	// refuse files not marked clean
+	if err := u.verifyScanStatus(file); err != nil {
+		return nil, err
+	}
 
-	if params.Secret != "" {
-		// get secret info
-		secret, err := u.verifySecretToken(ctx, params.Secret)
-		if err != nil {
-			return nil, err
-		}
+	// check permission, the owner doesn't need the link secret or password
+	if !isFileOwner(file, userId) {
+		if params.Secret != "" {
+			// get secret info
+			secret, err := u.verifySecretToken(ctx, params.Secret)
+			if err != nil {
+				return nil, err
+			}
 
-		// secret invalid
-		if file.SecretId != secret.UUID {
-			return nil, fmt.Errorf("permission denied")
-		}
-	} else {
-		// secret required
-		if file.SecretId != "" {
-			// no request download found
-			if file.DownloadPassword == "" {
+			// secret invalid
+			if file.SecretId != secret.UUID {
 				return nil, fmt.Errorf("permission denied")
 			}
+		} else {
+			// secret required
+			if file.SecretId != "" {
+				// no request download found
+				if file.DownloadPassword == "" {
+					return nil, fmt.Errorf("permission denied")
+				}
+			}
 		}
-	}
-
-	// password invalid
-	if params.DownloadPassword != file.DownloadPassword {
-		return nil, fmt.Errorf("permission denied")
-	}
 
-	//
-	if file.CreatedBy != userId {
-		return nil, fmt.Errorf("permission denied")
+		// password invalid, only a file with a password can be guessed
+		if file.DownloadPassword != "" {
+			if err := u.reserveAttempt(ctx, lockoutModel.LOCKOUT_KIND_DOWNLOAD_PASSWORD, file.UUID); err != nil {
+				return nil, err
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(params.DownloadPassword), []byte(file.DownloadPassword)) != 1 {
+			return nil, fmt.Errorf("permission denied")
+		}
+		if file.DownloadPassword != "" {
+			u.releaseAttempt(ctx, lockoutModel.LOCKOUT_KIND_DOWNLOAD_PASSWORD, file.UUID)
+		}
 	}
 
 	// end validation
 
-	var sas *azBlobModel.DownloadSASResponse
-	downloadFileName := map[bool]string{true: file.Token + file.Ext, false: file.Token}[file.Ext != ""]
-
-	if file.SecretId == "" {
-		// public download
-		downloadFileName = path.Join("public", downloadFileName)
-		sas, err = u.azBlobSv.DownloadSAS(ctx, &azBlobModel.DownloadSASRequest{
-			FileName: downloadFileName,
-		})
-		if err != nil {
-			log.Error("usecase.azBlobSv.DownloadSAS", err)
-			return nil, err
-		}
-	} else {
-		// private download
-		downloadFileName = path.Join("private", file.SecretId, downloadFileName)
-		sas, err = u.azBlobSv.DownloadSAS(ctx, &azBlobModel.DownloadSASRequest{
-			FileName: downloadFileName,
-		})
-		if err != nil {
-			log.Error("usecase.azBlobSv.DownloadSAS", err)
-			return nil, err
-		}
+	// public/private download
+	sas, err := u.azBlobSv.DownloadSAS(ctx, &azBlobModel.DownloadSASRequest{
+		FileName: getBlobName(file),
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.DownloadSAS", err)
+		return nil, err
 	}
 
 	return &storageModel.DownloadResponse{
@@ -97,8 +91,13 @@ func (u *usecase) RequestDownload(ctx context.Context, userId int64, params *sto
 		return nil, err
 	}
 
-	// get secret info
-	secret, err := u.verifySecretToken(ctx, params.Secret)
+	// refuse files not marked clean
+	if err := u.verifyScanStatus(file); err != nil {
+		return nil, err
+	}
+
+	// get secret info, sharing a private file needs the pin code
+	secret, err := u.verifySecretPin(ctx, params.Secret, params.PinCode)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +111,11 @@ func (u *usecase) RequestDownload(ctx context.Context, userId int64, params *sto
 
 	downloadPassword := file.DownloadPassword
 	if downloadPassword == "" {
-		downloadPassword = generateDownloadPassword()
+		downloadPassword, err = generateDownloadPassword()
+		if err != nil {
+			log.Error("usecase.generateDownloadPassword", err)
+			return nil, err
+		}
 		if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
 			UUID:             file.UUID,
 			DownloadPassword: downloadPassword,
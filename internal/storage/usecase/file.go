@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"medioa/constants"
+	storageModel "medioa/internal/storage/models"
+	commonModel "medioa/models"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) ListSecretFiles(ctx context.Context, userId int64, params *storageModel.ListSecretFilesRequest) (*storageModel.ListFileResponse, error) {
+	return u.listSecretFiles(ctx, params, false)
+}
+
+func (u *usecase) listSecretFiles(ctx context.Context, params *storageModel.ListSecretFilesRequest, isDeleted bool) (*storageModel.ListFileResponse, error) {
+	log := log.New("usecase", "listSecretFiles")
+
+	// validation
+
+	// get secret info, private listings need the pin code
+	secret, err := u.verifySecretPin(ctx, params.Secret, params.PinCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	isPending := false
+	files, err := u.storageSv.GetListPaging(ctx, &storageModel.RequestParams{
+		RequestParams: commonModel.RequestParams{
+			Page:    params.Page,
+			Size:    params.Size,
+			SortBy:  params.SortBy,
+			OrderBy: params.OrderBy,
+		},
+		ConfigQuery: constants.CONFIG_QUERY_GET_ALL,
+		SecretId:    secret.UUID,
+		Type:        params.Type,
+		Ext:         params.Ext,
+		FileName:    params.FileName,
+		CreatedFrom: params.From,
+		CreatedTo:   params.To,
+		IsDeleted:   &isDeleted,
+		IsPending:   &isPending,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetListPaging", err)
+		return nil, err
+	}
+
+	records := make([]*storageModel.FileResponse, 0, len(files.Records))
+	for _, file := range files.Records {
+		records = append(records, &storageModel.FileResponse{
+			FileId:     file.UUID,
+			FileName:   file.FileName,
+			FileSize:   file.FileSize,
+			Type:       file.Type,
+			Ext:        file.Ext,
+			Token:      file.Token,
+			Url:        file.DownloadUrl,
+			CreatedAt:  file.CreatedAt,
+			Sha256:     file.Sha256,
+			ScanStatus: file.ScanStatus,
+			DeletedAt:  file.DeletedAt,
+			ExpiredAt:  timeOrNil(file.ExpiredAt),
+		})
+	}
+
+	return &storageModel.ListFileResponse{
+		Page:    files.Page,
+		Size:    files.Size,
+		Count:   files.Count,
+		Records: records,
+	}, nil
+}
+
+func (u *usecase) Delete(ctx context.Context, userId int64, params *storageModel.DeleteRequest) (*storageModel.DeleteResponse, error) {
+
+	// validation
+
+	// get file info
+	file, err := u.verifyFileInfo(ctx, params.FileId, params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// private file must be deleted with secret
+	if file.SecretId != "" {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	// end validation
+
+	return u.trashFile(ctx, userId, file)
+}
+
+func (u *usecase) DeleteWithSecret(ctx context.Context, userId int64, params *storageModel.DeleteWithSecretRequest) (*storageModel.DeleteResponse, error) {
+
+	// validation
+
+	// get file info
+	file, err := u.getFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// get secret info, deleting needs the pin code
+	secret, err := u.verifySecretPin(ctx, params.Secret, params.PinCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// check permission
+	if file.SecretId != secret.UUID {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	// end validation
+
+	return u.trashFile(ctx, userId, file)
+}
+
+// deleteFile marks the record as releasing, releases the blob, then removes the record. A marked
+// record is hidden and no longer references its blob, it stays until the blob is released so
+// a failed release is retried by the next purge.
+func (u *usecase) deleteFile(ctx context.Context, userId int64, file *storageModel.Response) (*storageModel.DeleteResponse, error) {
+	log := log.New("usecase", "deleteFile")
+
+	// the blob may be shared with other files, drop this reference first
+	blobName := getBlobName(file)
+	deletedAt := time.Now()
+	if file.DeletedAt != nil {
+		deletedAt = *file.DeletedAt
+	}
+	isReleasing := true
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:        file.UUID,
+		BlobName:    blobName,
+		DeletedAt:   &deletedAt,
+		IsReleasing: &isReleasing,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	if err := u.releaseBlob(ctx, blobName); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.storageSv.Delete(ctx, userId, &storageModel.SaveRequest{
+		UUID: file.UUID,
+	}); err != nil {
+		log.Error("usecase.storageSv.Delete", err)
+		return nil, err
+	}
+
+	return &storageModel.DeleteResponse{
+		FileId: file.UUID,
+	}, nil
+}
@@ -1,25 +1,39 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
 	"medioa/constants"
 	"medioa/pkg/xtype"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/vukyn/kuery/log"
 
+	lockoutModel "medioa/internal/lockout/models"
 	secretModel "medioa/internal/secret/models"
 	storageModel "medioa/internal/storage/models"
 
-	"github.com/vukyn/kuery/cryp"
 	"github.com/zRedShift/mimemagic"
 )
 
-func generateDownloadPassword() string {
-	return cryp.HashUUID()[0:8]
+func generateDownloadPassword() (string, error) {
+	buf := make([]byte, constants.DOWNLOAD_PASSWORD_SIZE)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
 }
 
 func sniffMimeType(file xtype.File) (string, error) {
@@ -32,7 +46,13 @@ func sniffMimeType(file xtype.File) (string, error) {
 	}
 	defer reader.Close()
 
-	mimeType, err := mimemagic.MatchReader(reader, file.Filename)
+	return sniffMimeTypeReader(reader, file.Filename)
+}
+
+func sniffMimeTypeReader(reader io.Reader, fileName string) (string, error) {
+	log := log.New("usecase", "sniffMimeTypeReader")
+
+	mimeType, err := mimemagic.MatchReader(reader, fileName)
 	if err != nil {
 		log.Error("mimemagic.MatchReader", err)
 		return "", err
@@ -74,6 +94,153 @@ func getDownloadUrl(host, fileId, token string) string {
 	return downloadUrl
 }
 
+// getBlobName returns the blob path of a file: public/<token><ext> or private/<secretId>/<token><ext>,
+// deduplicated files reference the blob of another file by blob name
+func getBlobName(file *storageModel.Response) string {
+	if file.BlobName != "" {
+		return file.BlobName
+	}
+	fileName := map[bool]string{true: file.Token + file.Ext, false: file.Token}[file.Ext != ""]
+	if file.SecretId == "" {
+		return path.Join("public", fileName)
+	}
+	return path.Join("private", file.SecretId, fileName)
+}
+
+// getExpiredAt returns the lifetime (in seconds) and expiry time of an upload, zero means never expire
+func getExpiredAt(lifeTime int64, expireAt time.Time) (int64, time.Time, error) {
+	if lifeTime < 0 {
+		return 0, time.Time{}, fmt.Errorf("life time is invalid")
+	}
+	if lifeTime > 0 && !expireAt.IsZero() {
+		return 0, time.Time{}, fmt.Errorf("only one of life time or expire at is allowed")
+	}
+
+	now := time.Now()
+	if lifeTime > 0 {
+		return lifeTime, now.Add(time.Duration(lifeTime) * time.Second), nil
+	}
+	if !expireAt.IsZero() {
+		if !expireAt.After(now) {
+			return 0, time.Time{}, fmt.Errorf("expire at must be in the future")
+		}
+		return int64(expireAt.Sub(now).Seconds()), expireAt, nil
+	}
+	return 0, time.Time{}, nil
+}
+
+func validateChunkIndex(chunkIndex, totalChunks int64) error {
+	if totalChunks <= 0 {
+		return fmt.Errorf("total chunks must be greater than 0")
+	}
+	if chunkIndex < 0 || chunkIndex >= totalChunks {
+		return fmt.Errorf("chunk index must be in range [0, %d)", totalChunks)
+	}
+	return nil
+}
+
+// getCommitBlockIds returns block ids ordered by chunk index, all chunks must be staged
+func getCommitBlockIds(file *storageModel.Response) ([]string, error) {
+	if file.TotalChunks <= 0 || len(file.ChunkIds) == 0 {
+		return nil, fmt.Errorf("no chunks to commit")
+	}
+	missing := make([]int64, 0)
+	for idx := int64(0); idx < file.TotalChunks; idx++ {
+		if idx >= int64(len(file.ChunkIds)) || file.ChunkIds[idx] == "" {
+			missing = append(missing, idx)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing chunks: %v", missing)
+	}
+	if int64(len(file.ChunkIds)) > file.TotalChunks {
+		return nil, fmt.Errorf("received more chunks than total chunks")
+	}
+	// the type is recorded once chunk 0 passed the upload policy
+	if file.Type == "" {
+		return nil, fmt.Errorf("first chunk has not been checked")
+	}
+	return file.ChunkIds, nil
+}
+
+// verifyChecksum compares the digest (hex) of a file with the given checksum, skipped when no checksum is given
+func verifyChecksum(file xtype.File, algorithm, checksum string) error {
+	log := log.New("usecase", "verifyChecksum")
+
+	if checksum == "" {
+		return nil
+	}
+
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return err
+	}
+	expected, err := hex.DecodeString(checksum)
+	if err != nil {
+		return fmt.Errorf("invalid checksum")
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		log.Error("file.Open", err)
+		return err
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(h, reader); err != nil {
+		log.Error("io.Copy", err)
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), expected) {
+		return fmt.Errorf("checksum mismatch")
+	}
+
+	return nil
+}
+
+// newChecksumHash returns the hash of a checksum algorithm
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case constants.CHECKSUM_ALGORITHM_MD5:
+		return md5.New(), nil
+	case constants.CHECKSUM_ALGORITHM_SHA1:
+		return sha1.New(), nil
+	case constants.CHECKSUM_ALGORITHM_SHA256:
+		return sha256.New(), nil
+	case constants.CHECKSUM_ALGORITHM_CRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+}
+
+// sniffStream sniffs the mime type of a stream by content, only the sniffed head is buffered
+// and it is replayed in front of the returned reader.
+func sniffStream(reader io.Reader) (io.Reader, mimemagic.MediaType, error) {
+	var head bytes.Buffer
+	mediaType, err := mimemagic.MatchReader(io.TeeReader(reader, &head), "")
+	if err != nil {
+		return nil, mediaType, err
+	}
+	return io.MultiReader(&head, reader), mediaType, nil
+}
+
+func isExpired(file *storageModel.Response) bool {
+	return !file.ExpiredAt.IsZero() && !file.ExpiredAt.After(time.Now())
+}
+
+// isFileOwner reports whether an authenticated caller uploaded the file, anonymous callers own nothing
+func isFileOwner(file *storageModel.Response, userId int64) bool {
+	return userId != 0 && file.CreatedBy == userId
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func (u *usecase) verifySecretToken(ctx context.Context, secretToken string) (*secretModel.Response, error) {
 	log := log.New("usecase", "verifySecretToken")
 
@@ -81,8 +248,25 @@ func (u *usecase) verifySecretToken(ctx context.Context, secretToken string) (*s
 		return nil, fmt.Errorf("secret token is required")
 	}
 
+	claims, err := u.parseAccessToken(secretToken)
+	if err != nil {
+		return nil, fmt.Errorf("secret token is invalid")
+	}
+
+	// signed out or reset sessions stop working before their access tokens expire
+	session, err := u.sessionSv.GetOne(ctx, &secretModel.SessionRequestParams{
+		UUID: claims.SessionId,
+	})
+	if err != nil {
+		log.Error("usecase.sessionSv.GetOne", err)
+		return nil, err
+	}
+	if session == nil || !session.IsActive() || session.SecretId != claims.Subject {
+		return nil, fmt.Errorf("secret token is invalid")
+	}
+
 	secret, err := u.secretSv.GetOne(ctx, &secretModel.RequestParams{
-		AccessToken: secretToken,
+		UUID: claims.Subject,
 	})
 	if err != nil {
 		log.Error("usecase.secretSv.GetOne", err)
@@ -95,6 +279,61 @@ func (u *usecase) verifySecretToken(ctx context.Context, secretToken string) (*s
 	return secret, nil
 }
 
+// verifySecretPin is verifySecretToken for sensitive operations, the pin code is a second factor
+func (u *usecase) verifySecretPin(ctx context.Context, secretToken, pinCode string) (*secretModel.Response, error) {
+	secret, err := u.verifySecretToken(ctx, secretToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinCode == "" {
+		return nil, fmt.Errorf("pin code is required")
+	}
+	if err := u.reserveAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_PIN, secret.UUID); err != nil {
+		return nil, err
+	}
+	if err := comparePinCode(secret.PinCode, pinCode); err != nil {
+		return nil, fmt.Errorf("pin code is incorrect")
+	}
+	u.releaseAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_PIN, secret.UUID)
+	u.upgradePinCode(ctx, secret, pinCode)
+
+	return secret, nil
+}
+
+// verifyUploadOwner lets only the secret of a private upload, or the principal that
+// started a public one, add chunks to it or commit it
+func verifyUploadOwner(userId int64, file *storageModel.Response, secretId string) error {
+	if file.SecretId != secretId {
+		return fmt.Errorf("permission denied")
+	}
+	if secretId == "" && file.CreatedBy != userId {
+		return fmt.Errorf("permission denied")
+	}
+
+	return nil
+}
+
+func (u *usecase) verifyMasterSecret(ctx context.Context, secretToken string) (*secretModel.Response, error) {
+	secret, err := u.verifySecretToken(ctx, secretToken)
+	if err != nil {
+		return nil, err
+	}
+	if !secret.IsMaster {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	return secret, nil
+}
+
+// verifyMasterPin is verifyMasterSecret for operations on the credentials or files of other secrets
+func (u *usecase) verifyMasterPin(ctx context.Context, secretToken, pinCode string) (*secretModel.Response, error) {
+	if _, err := u.verifyMasterSecret(ctx, secretToken); err != nil {
+		return nil, err
+	}
+	return u.verifySecretPin(ctx, secretToken, pinCode)
+}
+
 func (u *usecase) verifyFileInfo(ctx context.Context, fileId, token string) (*storageModel.Response, error) {
 	log := log.New("usecase", "verifyFileInfo")
 
@@ -106,9 +345,11 @@ func (u *usecase) verifyFileInfo(ctx context.Context, fileId, token string) (*st
 		return nil, fmt.Errorf("token is required")
 	}
 
+	isDeleted := false
 	file, err := u.storageSv.GetOne(ctx, &storageModel.RequestParams{
-		UUID:  fileId,
-		Token: token,
+		UUID:      fileId,
+		Token:     token,
+		IsDeleted: &isDeleted,
 	})
 	if err != nil {
 		log.Error("usecase.storageSv.GetOne", err)
@@ -117,6 +358,9 @@ func (u *usecase) verifyFileInfo(ctx context.Context, fileId, token string) (*st
 	if file == nil {
 		return nil, fmt.Errorf("file not found")
 	}
+	if isExpired(file) {
+		return nil, fmt.Errorf("file has expired")
+	}
 
 	return file, nil
 }
@@ -128,8 +372,36 @@ func (u *usecase) getFileById(ctx context.Context, fileId string) (*storageModel
 		return nil, fmt.Errorf("file id is required")
 	}
 
+	isDeleted := false
+	file, err := u.storageSv.GetOne(ctx, &storageModel.RequestParams{
+		UUID:      fileId,
+		IsDeleted: &isDeleted,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetOne", err)
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("file not found")
+	}
+	if isExpired(file) {
+		return nil, fmt.Errorf("file has expired")
+	}
+
+	return file, nil
+}
+
+func (u *usecase) getTrashedFileById(ctx context.Context, fileId string) (*storageModel.Response, error) {
+	log := log.New("usecase", "getTrashedFileById")
+
+	if fileId == "" {
+		return nil, fmt.Errorf("file id is required")
+	}
+
+	isDeleted := true
 	file, err := u.storageSv.GetOne(ctx, &storageModel.RequestParams{
-		UUID: fileId,
+		UUID:      fileId,
+		IsDeleted: &isDeleted,
 	})
 	if err != nil {
 		log.Error("usecase.storageSv.GetOne", err)
@@ -138,6 +410,9 @@ func (u *usecase) getFileById(ctx context.Context, fileId string) (*storageModel
 	if file == nil {
 		return nil, fmt.Errorf("file not found")
 	}
+	if isExpired(file) {
+		return nil, fmt.Errorf("file has expired")
+	}
 
 	return file, nil
 }
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	storageModel "medioa/internal/storage/models"
+	"reflect"
+	"testing"
+)
+
+func TestGetCommitBlockIds(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    *storageModel.Response
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "all chunks staged",
+			file: &storageModel.Response{Type: "image/png", TotalChunks: 3, ChunkIds: []string{"a", "b", "c"}},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:    "no chunks",
+			file:    &storageModel.Response{Type: "image/png", TotalChunks: 3},
+			wantErr: true,
+		},
+		{
+			name:    "no total chunks",
+			file:    &storageModel.Response{Type: "image/png", ChunkIds: []string{"a"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing chunk in the middle",
+			file:    &storageModel.Response{Type: "image/png", TotalChunks: 3, ChunkIds: []string{"a", "", "c"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing last chunks",
+			file:    &storageModel.Response{Type: "image/png", TotalChunks: 3, ChunkIds: []string{"a"}},
+			wantErr: true,
+		},
+		{
+			name:    "more chunks than total",
+			file:    &storageModel.Response{Type: "image/png", TotalChunks: 2, ChunkIds: []string{"a", "b", "c"}},
+			wantErr: true,
+		},
+		{
+			name:    "first chunk not checked",
+			file:    &storageModel.Response{TotalChunks: 2, ChunkIds: []string{"a", "b"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getCommitBlockIds(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCommitBlockIds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCommitBlockIds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
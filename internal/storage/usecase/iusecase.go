@@ -8,8 +8,12 @@ import (
 type IUsecase interface {
 	GetFileInfo(ctx context.Context, userId int64, params *models.GetFileInfoRequest) (*models.GetFileInfoResponse, error)
 	Upload(ctx context.Context, userId int64, params *models.UploadRequest) (*models.UploadResponse, error)
+	UploadStream(ctx context.Context, userId int64, params *models.UploadStreamRequest) (*models.UploadResponse, error)
 	UploadChunk(ctx context.Context, userId int64, params *models.UploadChunkRequest) (*models.UploadChunkResponse, error)
 	CommitChunk(ctx context.Context, userId int64, params *models.CommitChunkRequest) (*models.CommitChunkResponse, error)
+	UploadStatus(ctx context.Context, userId int64, params *models.UploadStatusRequest) (*models.UploadStatusResponse, error)
+	PresignUpload(ctx context.Context, userId int64, params *models.PresignUploadRequest) (*models.PresignUploadResponse, error)
+	FinalizeUpload(ctx context.Context, userId int64, params *models.FinalizeUploadRequest) (*models.UploadResponse, error)
 	UploadWithSecret(ctx context.Context, userId int64, params *models.UploadWithSecretRequest) (*models.UploadResponse, error)
 	UploadChunkWithSecret(ctx context.Context, userId int64, params *models.UploadChunkWithSecretRequest) (*models.UploadChunkResponse, error)
 	CommitChunkWithSecret(ctx context.Context, userId int64, params *models.CommitChunkRequest) (*models.CommitChunkResponse, error)
@@ -17,5 +21,28 @@ type IUsecase interface {
 	RequestDownload(ctx context.Context, userId int64, params *models.RequestDownloadRequest) (*models.RequestDownloadResponse, error)
 	CreateSecret(ctx context.Context, userId int64, params *models.CreateSecretRequest) (*models.CreateSecretResponse, error)
 	RetrieveSecret(ctx context.Context, userId int64, params *models.RetrieveSecretRequest) (*models.RetrieveSecretResponse, error)
+	RefreshSecret(ctx context.Context, userId int64, params *models.RefreshSecretRequest) (*models.RefreshSecretResponse, error)
+	LogoutSecret(ctx context.Context, userId int64, params *models.LogoutSecretRequest) (*models.LogoutSecretResponse, error)
 	ResetPinCode(ctx context.Context, userId int64, params *models.ResetPinCodeRequest) (int64, error)
+	ListSecretFiles(ctx context.Context, userId int64, params *models.ListSecretFilesRequest) (*models.ListFileResponse, error)
+	Delete(ctx context.Context, userId int64, params *models.DeleteRequest) (*models.DeleteResponse, error)
+	DeleteWithSecret(ctx context.Context, userId int64, params *models.DeleteWithSecretRequest) (*models.DeleteResponse, error)
+	ListSecretTrash(ctx context.Context, userId int64, params *models.ListSecretFilesRequest) (*models.ListFileResponse, error)
+	RestoreWithSecret(ctx context.Context, userId int64, params *models.RestoreWithSecretRequest) (*models.RestoreResponse, error)
+	PurgeWithSecret(ctx context.Context, userId int64, params *models.DeleteWithSecretRequest) (*models.DeleteResponse, error)
+	PurgeTrash(ctx context.Context) (int64, error)
+	SweepExpired(ctx context.Context) (int64, error)
+	CountPendingUploads(ctx context.Context, userId int64, params *models.PendingUploadRequest) (*models.PendingUploadResponse, error)
+	CleanupPendingUploads(ctx context.Context) (int64, error)
+	GetStorageStats(ctx context.Context, userId int64, params *models.StorageStatsRequest) (*models.StorageStatsResponse, error)
+	ListSecrets(ctx context.Context, userId int64, params *models.ListSecretsRequest) (*models.ListSecretResponse, error)
+	ListFiles(ctx context.Context, userId int64, params *models.ListFilesRequest) (*models.ListFileResponse, error)
+	AdminDownload(ctx context.Context, userId int64, params *models.AdminFileRequest) (*models.DownloadResponse, error)
+	AdminDelete(ctx context.Context, userId int64, params *models.AdminFileRequest) (*models.DeleteResponse, error)
+	ResetSecretPassword(ctx context.Context, userId int64, params *models.ResetSecretPasswordRequest) (int64, error)
+	ResetSecretPinCode(ctx context.Context, userId int64, params *models.ResetSecretPinCodeRequest) (int64, error)
+	TusCreate(ctx context.Context, userId int64, params *models.TusCreateRequest) (*models.TusCreateResponse, error)
+	TusHead(ctx context.Context, userId int64, params *models.TusHeadRequest) (*models.TusHeadResponse, error)
+	TusPatch(ctx context.Context, userId int64, params *models.TusPatchRequest) (*models.TusPatchResponse, error)
+	TusDelete(ctx context.Context, userId int64, params *models.TusDeleteRequest) error
 }
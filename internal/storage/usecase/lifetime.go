@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	storageModel "medioa/internal/storage/models"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+)
+
+// SweepExpired permanently deletes files which passed their lifetime, including trashed ones.
+func (u *usecase) SweepExpired(ctx context.Context) (int64, error) {
+	log := log.New("usecase", "SweepExpired")
+
+	files, err := u.storageSv.GetList(ctx, &storageModel.RequestParams{
+		ExpiredBefore: time.Now(),
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetList", err)
+		return 0, err
+	}
+
+	swept := int64(0)
+	for _, file := range files {
+		// keep going, failed files are retried on next run
+		if _, err := u.deleteFile(ctx, file.CreatedBy, file); err != nil {
+			continue
+		}
+		swept++
+	}
+	if swept > 0 {
+		log.Info("swept %d of %d expired files", swept, len(files))
+	}
+
+	return swept, nil
+}
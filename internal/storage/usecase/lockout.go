@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	authModel "medioa/internal/auth/models"
+	lockoutModel "medioa/internal/lockout/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+// lockoutSubjects counts attempts against the account and the client ip
+func lockoutSubjects(ctx context.Context, account string) []lockoutModel.Subject {
+	return []lockoutModel.Subject{
+		lockoutModel.Account(account),
+		lockoutModel.IP(authModel.GetPrincipal(ctx).ClientIP),
+	}
+}
+
+// reserveAttempt counts an attempt before it is verified, it is refused while the account
+// or the client ip is locked out. A failed attempt stays counted.
+func (u *usecase) reserveAttempt(ctx context.Context, kind, account string) error {
+	log := log.New("usecase", "reserveAttempt")
+
+	if err := u.lockoutSv.Reserve(ctx, kind, lockoutSubjects(ctx, account)...); err != nil {
+		if _, ok := err.(*lockoutModel.LockedError); !ok {
+			log.Error("usecase.lockoutSv.Reserve", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseAttempt gives back a reserved attempt that succeeded
+func (u *usecase) releaseAttempt(ctx context.Context, kind, account string) {
+	log := log.New("usecase", "releaseAttempt")
+
+	if err := u.lockoutSv.Release(ctx, kind, lockoutSubjects(ctx, account)...); err != nil {
+		log.Error("usecase.lockoutSv.Release", err)
+	}
+}
+
+// resetAttempt forgets the attempts of the account, the client ip is shared
+// by other callers so it keeps counting
+func (u *usecase) resetAttempt(ctx context.Context, kind, account string) {
+	log := log.New("usecase", "resetAttempt")
+
+	if err := u.lockoutSv.Reset(ctx, kind, lockoutModel.Account(account)); err != nil {
+		log.Error("usecase.lockoutSv.Reset", err)
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	storageModel "medioa/internal/storage/models"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) CountPendingUploads(ctx context.Context, userId int64, params *storageModel.PendingUploadRequest) (*storageModel.PendingUploadResponse, error) {
+	log := log.New("usecase", "CountPendingUploads")
+
+	// validation
+
+	// get master secret info
+	if _, err := u.verifyMasterSecret(ctx, params.Secret); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	isPending := true
+	pending, err := u.storageSv.Count(ctx, &storageModel.RequestParams{
+		IsPending: &isPending,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.Count", err)
+		return nil, err
+	}
+
+	abandoned, err := u.storageSv.Count(ctx, &storageModel.RequestParams{
+		IsPending: &isPending,
+		CreatedTo: u.pendingUploadDeadline(),
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.Count", err)
+		return nil, err
+	}
+
+	return &storageModel.PendingUploadResponse{
+		Pending:    pending,
+		Abandoned:  abandoned,
+		PendingTTL: u.cfg.Upload.PendingTTL,
+	}, nil
+}
+
+// CleanupPendingUploads deletes chunked, tus and presigned uploads left pending longer than the pending ttl.
+func (u *usecase) CleanupPendingUploads(ctx context.Context) (int64, error) {
+	log := log.New("usecase", "CleanupPendingUploads")
+
+	isPending := true
+	files, err := u.storageSv.GetList(ctx, &storageModel.RequestParams{
+		IsPending: &isPending,
+		CreatedTo: u.pendingUploadDeadline(),
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetList", err)
+		return 0, err
+	}
+
+	cleaned := int64(0)
+	for _, file := range files {
+		// keep going, failed uploads are retried on next run
+		if _, err := u.deleteFile(ctx, file.CreatedBy, file); err != nil {
+			continue
+		}
+		cleaned++
+	}
+	if cleaned > 0 {
+		log.Info("cleaned %d of %d abandoned uploads", cleaned, len(files))
+	}
+
+	return cleaned, nil
+}
+
+func (u *usecase) pendingUploadDeadline() time.Time {
+	return time.Now().Add(-time.Duration(u.cfg.Upload.PendingTTL) * time.Minute)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"medioa/constants"
+	azBlobModel "medioa/internal/azblob/models"
+	storageModel "medioa/internal/storage/models"
+	"medioa/pkg/xtype"
+	"slices"
+	"strings"
+
+	"github.com/vukyn/kuery/log"
+	"github.com/zRedShift/mimemagic"
+)
+
+// checkUploadPolicy evaluates the upload policy on the sniffed content of a file,
+// the client file name is not trusted. A zero size skips the size limit.
+func (u *usecase) checkUploadPolicy(file xtype.File, size int64) error {
+	log := log.New("usecase", "checkUploadPolicy")
+
+	reader, err := file.Open()
+	if err != nil {
+		log.Error("file.Open", err)
+		return err
+	}
+	defer reader.Close()
+
+	return u.checkUploadPolicyReader(reader, size)
+}
+
+// checkBlobPolicy evaluates the upload policy on a stored blob, used when the content
+// didn't go through medioa as a file (url uploads)
+func (u *usecase) checkBlobPolicy(ctx context.Context, blobName string, size int64) error {
+	log := log.New("usecase", "checkBlobPolicy")
+
+	reader, err := u.azBlobSv.ReadBlob(ctx, &azBlobModel.ReadBlobRequest{
+		FileName: blobName,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.ReadBlob", err)
+		return err
+	}
+	defer reader.Close()
+
+	return u.checkUploadPolicyReader(reader, size)
+}
+
+func (u *usecase) checkUploadPolicyReader(reader io.Reader, size int64) error {
+	log := log.New("usecase", "checkUploadPolicyReader")
+
+	// sniff by content only
+	mediaType, err := mimemagic.MatchReader(reader, "")
+	if err != nil {
+		log.Error("mimemagic.MatchReader", err)
+		return err
+	}
+
+	return u.checkMediaPolicy(mediaType, size)
+}
+
+// checkMediaPolicy evaluates the upload policy on a sniffed media type
+func (u *usecase) checkMediaPolicy(mediaType mimemagic.MediaType, size int64) error {
+	mimeType := mediaType.MediaType()
+	policy := u.cfg.Upload.Policy
+
+	if matchMimeType(policy.DenyTypes, mimeType) || (len(policy.AllowTypes) > 0 && !matchMimeType(policy.AllowTypes, mimeType)) {
+		return &storageModel.PolicyError{
+			Reason:  constants.UPLOAD_POLICY_TYPE_NOT_ALLOWED,
+			Message: fmt.Sprintf("file type %s is not allowed", mimeType),
+		}
+	}
+
+	exts := make([]string, 0, len(mediaType.Extensions))
+	for _, ext := range mediaType.Extensions {
+		exts = append(exts, strings.ToLower(strings.TrimPrefix(ext, ".")))
+	}
+	if matchExt(policy.DenyExts, exts) || (len(policy.AllowExts) > 0 && !matchExt(policy.AllowExts, exts)) {
+		return &storageModel.PolicyError{
+			Reason:  constants.UPLOAD_POLICY_EXT_NOT_ALLOWED,
+			Message: fmt.Sprintf("file extension of %s is not allowed", mimeType),
+		}
+	}
+
+	return u.checkUploadSizePolicy(mimeType, size)
+}
+
+// checkUploadSizePolicy checks the size against the max size of a mime type, then of its family
+func (u *usecase) checkUploadSizePolicy(mimeType string, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	maxSizeMB, ok := u.cfg.Upload.Policy.TypeMaxSizeMB[mimeType]
+	if !ok {
+		family, _, _ := strings.Cut(mimeType, "/")
+		maxSizeMB, ok = u.cfg.Upload.Policy.TypeMaxSizeMB[family]
+	}
+	if ok && size > maxSizeMB<<20 {
+		return &storageModel.PolicyError{
+			Reason:  constants.UPLOAD_POLICY_SIZE_EXCEEDED,
+			Message: fmt.Sprintf("file size too large for %s (max: %dMB)", mimeType, maxSizeMB),
+		}
+	}
+
+	return nil
+}
+
+// matchMimeType matches a mime type against mime types or mime families
+func matchMimeType(list []string, mimeType string) bool {
+	family, _, _ := strings.Cut(mimeType, "/")
+	return slices.Contains(list, mimeType) || slices.Contains(list, family)
+}
+
+func matchExt(list []string, exts []string) bool {
+	for _, ext := range exts {
+		if slices.Contains(list, ext) {
+			return true
+		}
+	}
+	return false
+}
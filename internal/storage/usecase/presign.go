@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	azBlobModel "medioa/internal/azblob/models"
+	storageModel "medioa/internal/storage/models"
+	"path"
+	"strings"
+
+	"github.com/vukyn/kuery/log"
+
+	"github.com/google/uuid"
+)
+
+// PresignUpload reserves a blob and returns a short-lived write-only url for it, the file stays
+// pending until FinalizeUpload and is cleaned up like any abandoned upload.
+func (u *usecase) PresignUpload(ctx context.Context, userId int64, params *storageModel.PresignUploadRequest) (*storageModel.PresignUploadResponse, error) {
+	log := log.New("usecase", "PresignUpload")
+
+	// validation
+
+	if params.FileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	var secretId string
+	if params.Secret != "" {
+		secret, err := u.verifySecretToken(ctx, params.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secretId = secret.UUID
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	sas, err := u.azBlobSv.UploadSAS(ctx, &azBlobModel.UploadSASRequest{
+		SecretId: secretId,
+		FileName: params.FileName,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.UploadSAS", err)
+		return nil, err
+	}
+
+	// reserve the blob name
+	fileId := uuid.New().String()
+	fileName := strings.TrimSuffix(params.FileName, path.Ext(params.FileName))
+	if fileName == "" {
+		fileName = params.FileName
+	}
+	isPending := true
+	if _, err := u.storageSv.Create(ctx, userId, &storageModel.SaveRequest{
+		UUID:        fileId,
+		Token:       sas.Token,
+		DownloadUrl: getDownloadUrl(u.cfg.App.Host, fileId, sas.Token),
+		Ext:         sas.Ext,
+		FileName:    fileName,
+		SecretId:    secretId,
+		BlobName:    sas.FileName,
+		LifeTime:    lifeTime,
+		ExpiredAt:   expiredAt,
+		IsPending:   &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Create", err)
+		return nil, err
+	}
+
+	return &storageModel.PresignUploadResponse{
+		FileId:    fileId,
+		UploadUrl: sas.Url,
+		Method:    sas.Method,
+		Headers:   sas.Headers,
+		ExpiredAt: sas.ExpiredAt,
+	}, nil
+}
+
+// FinalizeUpload inspects a blob uploaded with a presigned url and commits its file
+func (u *usecase) FinalizeUpload(ctx context.Context, userId int64, params *storageModel.FinalizeUploadRequest) (*storageModel.UploadResponse, error) {
+	log := log.New("usecase", "FinalizeUpload")
+
+	// validation
+
+	file, err := u.getFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPresignedUpload(file) {
+		return nil, fmt.Errorf("presigned upload not found")
+	}
+
+	if file.FileSize > 0 {
+		return nil, fmt.Errorf("upload has been finalized")
+	}
+
+	var secretId string
+	if file.SecretId != "" {
+		secret, err := u.verifySecretToken(ctx, params.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secretId = secret.UUID
+	}
+	if err := verifyUploadOwner(userId, file, secretId); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	// verify the blob exists, sniff its type and size
+	blob, err := u.azBlobSv.InspectBlob(ctx, &azBlobModel.InspectBlobRequest{
+		FileName: file.BlobName,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.InspectBlob", err)
+		return nil, fmt.Errorf("file has not been uploaded")
+	}
+	if blob.FileSize == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	// content was never seen by medioa, check policy and scan before the file is committed
+	if err := u.checkBlobPolicy(ctx, file.BlobName, blob.FileSize); err != nil {
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	scanStatus, err := u.scanBlob(ctx, file.BlobName)
+	if err != nil {
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// reuse an identical blob if any
+	blobName, err := u.dedupBlob(ctx, userId, file.UUID, file.BlobName, blob.Sha256, file.SecretId)
+	if err != nil {
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// update file info
+	isPending := false
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:       file.UUID,
+		Type:       blob.MimeType,
+		FileSize:   blob.FileSize,
+		Sha256:     blob.Sha256,
+		BlobName:   blobName,
+		ScanStatus: scanStatus,
+		IsPending:  &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	return &storageModel.UploadResponse{
+		Url:        file.DownloadUrl,
+		FileId:     file.UUID,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   file.FileName,
+		FileSize:   blob.FileSize,
+		Sha256:     blob.Sha256,
+		ScanStatus: scanStatus,
+		ExpiredAt:  timeOrNil(file.ExpiredAt),
+	}, nil
+}
+
+// isPresignedUpload tells presigned uploads apart from chunked and tus uploads,
+// only they reserve a blob name before any content is received.
+func isPresignedUpload(file *storageModel.Response) bool {
+	return file.BlobName != "" && file.TotalChunks == 0 && file.UploadLength == 0
+}
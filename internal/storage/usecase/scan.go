@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"medioa/config"
+	"medioa/constants"
+	azBlobModel "medioa/internal/azblob/models"
+	storageModel "medioa/internal/storage/models"
+
+	"github.com/vukyn/kuery/log"
+)
+
+// scanBlob scans an uploaded blob before its file is committed and returns the scan status to store,
+// an empty status means scanning is disabled. Infected blobs are rejected unless quarantine is configured.
+func (u *usecase) scanBlob(ctx context.Context, blobName string) (string, error) {
+	log := log.New("usecase", "scanBlob")
+
+	if u.scanner == nil {
+		return "", nil
+	}
+
+	reader, err := u.azBlobSv.ReadBlob(ctx, &azBlobModel.ReadBlobRequest{
+		FileName: blobName,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.ReadBlob", err)
+		return "", err
+	}
+	defer reader.Close()
+
+	res, err := u.scanner.Scan(ctx, reader)
+	if err != nil {
+		log.Error("usecase.scanner.Scan", err)
+		if u.cfg.Scanner.Enforce {
+			return "", &storageModel.PolicyError{
+				Reason:  constants.UPLOAD_POLICY_SCAN_FAILED,
+				Message: "file could not be scanned",
+			}
+		}
+		// keep the file, it can't be downloaded until marked clean if scanning gets enforced
+		return constants.SCAN_STATUS_PENDING, nil
+	}
+
+	if res.Infected {
+		log.Info("blob %s is infected: %s", blobName, res.Signature)
+		if u.cfg.Scanner.InfectedAction != config.SCANNER_INFECTED_ACTION_QUARANTINE {
+			return "", &storageModel.PolicyError{
+				Reason:  constants.UPLOAD_POLICY_INFECTED,
+				Message: fmt.Sprintf("file is infected: %s", res.Signature),
+			}
+		}
+		return constants.SCAN_STATUS_INFECTED, nil
+	}
+
+	return constants.SCAN_STATUS_CLEAN, nil
+}
+
+// discardBlob deletes a blob rejected before its file is committed
+func (u *usecase) discardBlob(ctx context.Context, blobName string) {
+	log := log.New("usecase", "discardBlob")
+
+	if err := u.azBlobSv.DeleteBlob(ctx, &azBlobModel.DeleteBlobRequest{
+		FileName: blobName,
+	}); err != nil {
+		log.Error("usecase.azBlobSv.DeleteBlob", err)
+	}
+}
+
+// verifyScanStatus refuses uploads not finalized, quarantined files, and files not marked clean when scanning is enforced
+func (u *usecase) verifyScanStatus(file *storageModel.Response) error {
+	// an upload not finalized yet has nothing to scan nor share
+	if file.IsPending || len(file.ChunkIds) > 0 {
+		return fmt.Errorf("file not found")
+	}
+	if file.ScanStatus == constants.SCAN_STATUS_INFECTED {
+		return fmt.Errorf("file is quarantined")
+	}
+	if u.cfg.Scanner.Enforce && file.ScanStatus != constants.SCAN_STATUS_CLEAN {
+		return fmt.Errorf("file has not been scanned")
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"medioa/constants"
+	lockoutModel "medioa/internal/lockout/models"
 	secretModel "medioa/internal/secret/models"
 	storageModel "medioa/internal/storage/models"
 	"regexp"
@@ -11,10 +13,11 @@ import (
 	"github.com/vukyn/kuery/log"
 
 	"github.com/google/uuid"
-	"github.com/vukyn/kuery/cryp"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var pinCodeRegex = regexp.MustCompile(constants.SECRET_PIN_CODE_PATTERN)
+
 func (u *usecase) CreateSecret(ctx context.Context, userId int64, params *storageModel.CreateSecretRequest) (*storageModel.CreateSecretResponse, error) {
 	log := log.New("service", "CreateSecret")
 
@@ -31,42 +34,49 @@ func (u *usecase) CreateSecret(ctx context.Context, userId int64, params *storag
 	}
 
 	// check if pin code is valid
-	pinCodePattern := `^\d{4}$`
-	regex, _ := regexp.Compile(pinCodePattern)
-	if !regex.MatchString(params.PinCode) {
-		return nil, fmt.Errorf("pin code must be 4 digits")
+	if err := validatePinCode(params.PinCode); err != nil {
+		return nil, err
 	}
 
+	// a master secret has admin powers over every secret and file
 	isMaster := false
-	if params.MasterKey == u.cfg.Secret.SecretKey {
+	if params.MasterKey != "" && subtle.ConstantTimeCompare([]byte(params.MasterKey), []byte(u.cfg.Secret.SecretKey)) == 1 {
 		isMaster = true
 	}
 
 	// Save to database
 	_id := uuid.New().String()
-	accessToken := cryp.HashUUID()
 	if _, err := u.secretSv.Create(ctx, userId, &secretModel.SaveRequest{
-		UUID:        _id,
-		Username:    params.Username,
-		Password:    params.Password,
-		PinCode:     params.PinCode,
-		AccessToken: accessToken,
-		Type:        constants.SECRET_TYPE_MEDIA,
-		IsMaster:    isMaster,
+		UUID:     _id,
+		Username: params.Username,
+		Password: params.Password,
+		PinCode:  params.PinCode,
+		Type:     constants.SECRET_TYPE_MEDIA,
+		IsMaster: isMaster,
 	}); err != nil {
 		log.Error("service.secretSv.Create", err)
 		return nil, err
 	}
 
+	tokens, err := u.openSession(ctx, _id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &storageModel.CreateSecretResponse{
-		UserId:      _id,
-		AccessToken: accessToken,
+		UserId:              _id,
+		SecretTokenResponse: *tokens,
 	}, nil
 }
 
 func (u *usecase) RetrieveSecret(ctx context.Context, userId int64, params *storageModel.RetrieveSecretRequest) (*storageModel.RetrieveSecretResponse, error) {
 	log := log.New("service", "RetrieveSecret")
 
+	// count the attempt before verifying it, refused while locked out
+	if err := u.reserveAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_LOGIN, params.Username); err != nil {
+		return nil, err
+	}
+
 	// check if username exists
 	foundSecret, err := u.secretSv.GetOne(ctx, &secretModel.RequestParams{
 		Username: params.Username,
@@ -84,37 +94,102 @@ func (u *usecase) RetrieveSecret(ctx context.Context, userId int64, params *stor
 		log.Error("comparePassword", err)
 		return nil, fmt.Errorf("password is incorrect")
 	}
+	u.releaseAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_LOGIN, params.Username)
 
-	accessToken := cryp.HashUUID()
-	if _, err := u.secretSv.Update(ctx, userId, &secretModel.SaveRequest{
-		UUID:        foundSecret.UUID,
-		AccessToken: accessToken,
-	}); err != nil {
-		log.Error("service.secretSv.Update", err)
+	// a new session, sessions on other devices stay valid
+	tokens, err := u.openSession(ctx, foundSecret.UUID)
+	if err != nil {
 		return nil, err
 	}
 
 	return &storageModel.RetrieveSecretResponse{
-		UserId:      foundSecret.UUID,
-		AccessToken: accessToken,
+		UserId:              foundSecret.UUID,
+		SecretTokenResponse: *tokens,
+	}, nil
+}
+
+func (u *usecase) RefreshSecret(ctx context.Context, userId int64, params *storageModel.RefreshSecretRequest) (*storageModel.RefreshSecretResponse, error) {
+	session, tokens, err := u.rotateSession(ctx, params.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storageModel.RefreshSecretResponse{
+		UserId:              session.SecretId,
+		SecretTokenResponse: *tokens,
+	}, nil
+}
+
+func (u *usecase) LogoutSecret(ctx context.Context, userId int64, params *storageModel.LogoutSecretRequest) (*storageModel.LogoutSecretResponse, error) {
+	log := log.New("usecase", "LogoutSecret")
+
+	// validation
+	session, err := u.verifyRefreshToken(ctx, params.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	// end validation
+
+	sessions := []*secretModel.SessionResponse{session}
+	if params.All {
+		isRevoked := false
+		sessions, err = u.sessionSv.GetList(ctx, &secretModel.SessionRequestParams{
+			SecretId:  session.SecretId,
+			IsRevoked: &isRevoked,
+		})
+		if err != nil {
+			log.Error("usecase.sessionSv.GetList", err)
+			return nil, err
+		}
+	}
+
+	revoked, err := u.revokeSessions(ctx, sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storageModel.LogoutSecretResponse{
+		Revoked: revoked,
 	}, nil
 }
 
 func (u *usecase) ResetPinCode(ctx context.Context, userId int64, params *storageModel.ResetPinCodeRequest) (int64, error) {
 	log := log.New("service", "ResetPinCode")
 
+	// validation
+
 	// check if secret exists
-	foundSecret, err := u.secretSv.GetOne(ctx, &secretModel.RequestParams{
-		AccessToken: params.AccessToken,
-	})
+	foundSecret, err := u.verifySecretToken(ctx, params.AccessToken)
 	if err != nil {
-		log.Error("service.secretSv.GetOne", err)
 		return 0, err
 	}
-	if foundSecret == nil {
-		return 0, fmt.Errorf("secret not found")
+
+	// prove the secret with the old pin code or the password
+	if params.OldPinCode == "" && params.Password == "" {
+		return 0, fmt.Errorf("old pin code or password is required")
+	}
+	if err := u.reserveAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_PIN, foundSecret.UUID); err != nil {
+		return 0, err
+	}
+	switch {
+	case params.OldPinCode != "":
+		if err := comparePinCode(foundSecret.PinCode, params.OldPinCode); err != nil {
+			return 0, fmt.Errorf("pin code is incorrect")
+		}
+	case params.Password != "":
+		if err := comparePassword(foundSecret.Password, params.Password); err != nil {
+			return 0, fmt.Errorf("password is incorrect")
+		}
+	}
+	u.releaseAttempt(ctx, lockoutModel.LOCKOUT_KIND_SECRET_PIN, foundSecret.UUID)
+
+	// check if new pin code is valid
+	if err := validatePinCode(params.NewPinCode); err != nil {
+		return 0, err
 	}
 
+	// end validation
+
 	if _, err := u.secretSv.Update(ctx, userId, &secretModel.SaveRequest{
 		UUID:    foundSecret.UUID,
 		PinCode: params.NewPinCode,
@@ -129,3 +204,43 @@ func (u *usecase) ResetPinCode(ctx context.Context, userId int64, params *storag
 func comparePassword(hashedPassword, plainPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
 }
+
+// comparePinCode also accepts pin codes stored in plain text before they were hashed,
+// they are hashed by upgradePinCode once verified
+func comparePinCode(hashedPinCode, plainPinCode string) error {
+	if !isHashedPinCode(hashedPinCode) {
+		if subtle.ConstantTimeCompare([]byte(hashedPinCode), []byte(plainPinCode)) != 1 {
+			return bcrypt.ErrMismatchedHashAndPassword
+		}
+		return nil
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hashedPinCode), []byte(plainPinCode))
+}
+
+// upgradePinCode hashes a verified pin code still stored in plain text, it is retried
+// on the next verification if the update fails
+func (u *usecase) upgradePinCode(ctx context.Context, secret *secretModel.Response, plainPinCode string) {
+	log := log.New("usecase", "upgradePinCode")
+
+	if isHashedPinCode(secret.PinCode) {
+		return
+	}
+	if _, err := u.secretSv.Update(ctx, secret.CreatedBy, &secretModel.SaveRequest{
+		UUID:    secret.UUID,
+		PinCode: plainPinCode,
+	}); err != nil {
+		log.Error("usecase.secretSv.Update", err)
+	}
+}
+
+func isHashedPinCode(pinCode string) bool {
+	_, err := bcrypt.Cost([]byte(pinCode))
+	return err == nil
+}
+
+func validatePinCode(pinCode string) error {
+	if !pinCodeRegex.MatchString(pinCode) {
+		return fmt.Errorf("pin code must be 4 digits")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestComparePinCode(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() error = %v", err)
+	}
+	tests := []struct {
+		name       string
+		stored     string
+		pinCode    string
+		wantErr    bool
+		wantHashed bool
+	}{
+		{name: "hashed match", stored: string(hashed), pinCode: "1234", wantHashed: true},
+		{name: "hashed mismatch", stored: string(hashed), pinCode: "4321", wantErr: true, wantHashed: true},
+		{name: "legacy plain text match", stored: "1234", pinCode: "1234"},
+		{name: "legacy plain text mismatch", stored: "1234", pinCode: "4321", wantErr: true},
+		{name: "empty pin code", stored: "1234", pinCode: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := comparePinCode(tt.stored, tt.pinCode); (err != nil) != tt.wantErr {
+				t.Errorf("comparePinCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := isHashedPinCode(tt.stored); got != tt.wantHashed {
+				t.Errorf("isHashedPinCode() = %v, want %v", got, tt.wantHashed)
+			}
+		})
+	}
+}
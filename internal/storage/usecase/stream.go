@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	azBlobModel "medioa/internal/azblob/models"
+	storageModel "medioa/internal/storage/models"
+	"path"
+	"strings"
+
+	"github.com/vukyn/kuery/log"
+
+	"github.com/google/uuid"
+)
+
+// UploadStream uploads a raw body as it is received, the type is sniffed from the first bytes
+// so the policy is checked before anything is stored. Secret is optional, for private upload.
+func (u *usecase) UploadStream(ctx context.Context, userId int64, params *storageModel.UploadStreamRequest) (*storageModel.UploadResponse, error) {
+	log := log.New("usecase", "UploadStream")
+
+	// validation
+
+	if params.FileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	var secretId string
+	if params.Secret != "" {
+		secret, err := u.verifySecretToken(ctx, params.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secretId = secret.UUID
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// sniff mime type
+	reader, mediaType, err := sniffStream(params.Reader)
+	if err != nil {
+		log.Error("sniffStream", err)
+		return nil, err
+	}
+	mimeType := mediaType.MediaType()
+
+	// check upload policy, size is only known upfront with a content length
+	if err := u.checkMediaPolicy(mediaType, params.Size); err != nil {
+		return nil, err
+	}
+
+	// verify checksum while streaming
+	var checksum hash.Hash
+	var expected []byte
+	if params.Checksum != "" {
+		if checksum, err = newChecksumHash(params.ChecksumAlgorithm); err != nil {
+			return nil, err
+		}
+		if expected, err = hex.DecodeString(params.Checksum); err != nil {
+			return nil, fmt.Errorf("invalid checksum")
+		}
+		reader = io.TeeReader(reader, checksum)
+	}
+
+	// end validation
+
+	file, err := u.azBlobSv.UploadStream(ctx, &azBlobModel.UploadStreamRequest{
+		SessionId: params.SessionId,
+		SecretId:  secretId,
+		FileName:  params.FileName,
+		Size:      params.Size,
+		Reader:    reader,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.UploadStream", err)
+		return nil, err
+	}
+
+	if checksum != nil && !bytes.Equal(checksum.Sum(nil), expected) {
+		u.discardBlob(ctx, file.FileName)
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	// check upload policy on the streamed size
+	if err := u.checkUploadSizePolicy(mimeType, file.FileSize); err != nil {
+		u.discardBlob(ctx, file.FileName)
+		return nil, err
+	}
+
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, file.FileName)
+	if err != nil {
+		u.discardBlob(ctx, file.FileName)
+		return nil, err
+	}
+
+	// save to database
+	fileId := uuid.New().String()
+	fileName := strings.TrimSuffix(params.FileName, path.Ext(params.FileName))
+	if fileName == "" {
+		fileName = params.FileName
+	}
+	downloadUrl := getDownloadUrl(u.cfg.App.Host, fileId, file.Token)
+	isPending := true
+	if _, err := u.storageSv.Create(ctx, userId, &storageModel.SaveRequest{
+		UUID:        fileId,
+		Type:        mimeType,
+		Token:       file.Token,
+		DownloadUrl: downloadUrl,
+		Ext:         file.Ext,
+		FileName:    fileName,
+		FileSize:    file.FileSize,
+		SecretId:    secretId,
+		Sha256:      file.Sha256,
+		BlobName:    file.FileName,
+		ScanStatus:  scanStatus,
+		LifeTime:    lifeTime,
+		ExpiredAt:   expiredAt,
+		IsPending:   &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Create", err)
+		return nil, err
+	}
+
+	// reuse an identical blob if any, the file stays pending so it isn't its own duplicate
+	if _, err := u.dedupBlob(ctx, userId, fileId, file.FileName, file.Sha256, secretId); err != nil {
+		if _, err := u.deleteFile(ctx, userId, &storageModel.Response{UUID: fileId, BlobName: file.FileName}); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	isPending = false
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:      fileId,
+		IsPending: &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	return &storageModel.UploadResponse{
+		Url:        downloadUrl,
+		FileId:     fileId,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   fileName,
+		FileSize:   file.FileSize,
+		Sha256:     file.Sha256,
+		ScanStatus: scanStatus,
+		ExpiredAt:  timeOrNil(expiredAt),
+	}, nil
+}
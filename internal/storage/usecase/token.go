@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"medioa/constants"
+	secretModel "medioa/internal/secret/models"
+	storageModel "medioa/internal/storage/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/vukyn/kuery/log"
+)
+
+// secretClaims are the claims of a secret access token, the subject is the secret id
+type secretClaims struct {
+	jwt.RegisteredClaims
+	SessionId string `json:"sid"`
+}
+
+// openSession starts a new session of a secret, other sessions stay valid
+func (u *usecase) openSession(ctx context.Context, secretId string) (*storageModel.SecretTokenResponse, error) {
+	log := log.New("usecase", "openSession")
+
+	sessionId := uuid.New().String()
+	refreshToken, refreshHash, err := generateRefreshToken(sessionId)
+	if err != nil {
+		log.Error("usecase.generateRefreshToken", err)
+		return nil, err
+	}
+
+	if _, err := u.sessionSv.Create(ctx, &secretModel.SessionSaveRequest{
+		UUID:        sessionId,
+		SecretId:    secretId,
+		RefreshHash: refreshHash,
+		ExpiredAt:   time.Now().Add(u.refreshTokenTTL()),
+	}); err != nil {
+		log.Error("usecase.sessionSv.Create", err)
+		return nil, err
+	}
+
+	return u.issueTokens(secretId, sessionId, refreshToken)
+}
+
+// rotateSession exchanges a refresh token for a new pair, a refresh token used twice
+// means it leaked so the whole session is revoked
+func (u *usecase) rotateSession(ctx context.Context, refreshToken string) (*secretModel.SessionResponse, *storageModel.SecretTokenResponse, error) {
+	log := log.New("usecase", "rotateSession")
+
+	session, err := u.verifyRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newRefreshToken, newRefreshHash, err := generateRefreshToken(session.UUID)
+	if err != nil {
+		log.Error("usecase.generateRefreshToken", err)
+		return nil, nil, err
+	}
+
+	// a concurrent refresh with the same token rotated it first, it was used twice
+	affected, err := u.sessionSv.Rotate(ctx, &secretModel.RotateSessionRequest{
+		UUID:           session.UUID,
+		OldRefreshHash: session.RefreshHash,
+		RefreshHash:    newRefreshHash,
+		ExpiredAt:      time.Now().Add(u.refreshTokenTTL()),
+	})
+	if err != nil {
+		log.Error("usecase.sessionSv.Rotate", err)
+		return nil, nil, err
+	}
+	if affected == 0 {
+		if _, err := u.revokeSessions(ctx, []*secretModel.SessionResponse{session}); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("refresh token is invalid")
+	}
+
+	tokens, err := u.issueTokens(session.SecretId, session.UUID, newRefreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, tokens, nil
+}
+
+// verifyRefreshToken returns the active session of a refresh token
+func (u *usecase) verifyRefreshToken(ctx context.Context, refreshToken string) (*secretModel.SessionResponse, error) {
+	log := log.New("usecase", "verifyRefreshToken")
+
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refresh token is required")
+	}
+
+	sessionId, _, ok := strings.Cut(refreshToken, constants.SECRET_REFRESH_TOKEN_SEP)
+	if !ok {
+		return nil, fmt.Errorf("refresh token is invalid")
+	}
+
+	session, err := u.sessionSv.GetOne(ctx, &secretModel.SessionRequestParams{
+		UUID: sessionId,
+	})
+	if err != nil {
+		log.Error("usecase.sessionSv.GetOne", err)
+		return nil, err
+	}
+	if session == nil || !session.IsActive() {
+		return nil, fmt.Errorf("refresh token is invalid")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(session.RefreshHash), []byte(hashRefreshToken(refreshToken))) != 1 {
+		// an old refresh token of the session, revoke it
+		if _, err := u.revokeSessions(ctx, []*secretModel.SessionResponse{session}); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("refresh token is invalid")
+	}
+
+	return session, nil
+}
+
+func (u *usecase) revokeSessions(ctx context.Context, sessions []*secretModel.SessionResponse) (int64, error) {
+	log := log.New("usecase", "revokeSessions")
+
+	revokedAt := time.Now()
+	reqs := make([]*secretModel.SessionSaveRequest, 0, len(sessions))
+	for _, session := range sessions {
+		reqs = append(reqs, &secretModel.SessionSaveRequest{
+			UUID:      session.UUID,
+			RevokedAt: &revokedAt,
+		})
+	}
+	affected, err := u.sessionSv.UpdateMany(ctx, reqs)
+	if err != nil {
+		log.Error("usecase.sessionSv.UpdateMany", err)
+		return 0, err
+	}
+	return affected, nil
+}
+
+func (u *usecase) issueTokens(secretId, sessionId, refreshToken string) (*storageModel.SecretTokenResponse, error) {
+	log := log.New("usecase", "issueTokens")
+
+	now := time.Now()
+	accessTokenTTL := time.Duration(u.cfg.Secret.AccessTokenTTL) * time.Minute
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, secretClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    constants.SECRET_TOKEN_ISSUER,
+			Subject:   secretId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+		},
+		SessionId: sessionId,
+	}).SignedString([]byte(u.cfg.Secret.TokenKey))
+	if err != nil {
+		log.Error("jwt.SignedString", err)
+		return nil, err
+	}
+
+	return &storageModel.SecretTokenResponse{
+		AccessToken:      accessToken,
+		ExpiresIn:        int64(accessTokenTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(u.refreshTokenTTL().Seconds()),
+	}, nil
+}
+
+// parseAccessToken checks the signature and expiry of an access token, its session is checked by the caller
+func (u *usecase) parseAccessToken(accessToken string) (*secretClaims, error) {
+	claims := &secretClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (any, error) {
+		return []byte(u.cfg.Secret.TokenKey), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(constants.SECRET_TOKEN_ISSUER),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (u *usecase) refreshTokenTTL() time.Duration {
+	return time.Duration(u.cfg.Secret.RefreshTokenTTL) * time.Minute
+}
+
+// generateRefreshToken returns a refresh token and its stored hash,
+// the session id prefix finds the session without scanning hashes
+func generateRefreshToken(sessionId string) (string, string, error) {
+	buf := make([]byte, constants.SECRET_REFRESH_TOKEN_SIZE)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := sessionId + constants.SECRET_REFRESH_TOKEN_SEP + hex.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
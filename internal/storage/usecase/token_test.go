@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"medioa/config"
+	"medioa/constants"
+	secretModel "medioa/internal/secret/models"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySessions keeps sessions in memory for tests
+type memorySessions struct {
+	sync.Mutex
+	sessions map[string]*secretModel.SessionResponse
+}
+
+func (m *memorySessions) GetOne(ctx context.Context, params *secretModel.SessionRequestParams) (*secretModel.SessionResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	session, ok := m.sessions[params.UUID]
+	if !ok {
+		return nil, nil
+	}
+	res := *session
+	return &res, nil
+}
+
+func (m *memorySessions) GetList(ctx context.Context, params *secretModel.SessionRequestParams) ([]*secretModel.SessionResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	res := make([]*secretModel.SessionResponse, 0)
+	for _, session := range m.sessions {
+		if params.SecretId == "" || session.SecretId == params.SecretId {
+			s := *session
+			res = append(res, &s)
+		}
+	}
+	return res, nil
+}
+
+func (m *memorySessions) Create(ctx context.Context, params *secretModel.SessionSaveRequest) (*secretModel.SessionResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	session := &secretModel.SessionResponse{
+		UUID:        params.UUID,
+		SecretId:    params.SecretId,
+		RefreshHash: params.RefreshHash,
+		ExpiredAt:   params.ExpiredAt,
+		CreatedAt:   time.Now(),
+	}
+	m.sessions[params.UUID] = session
+	return session, nil
+}
+
+func (m *memorySessions) Update(ctx context.Context, params *secretModel.SessionSaveRequest) (*secretModel.SessionResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	session, ok := m.sessions[params.UUID]
+	if !ok {
+		return nil, nil
+	}
+	if params.RefreshHash != "" {
+		session.RefreshHash = params.RefreshHash
+	}
+	if !params.ExpiredAt.IsZero() {
+		session.ExpiredAt = params.ExpiredAt
+	}
+	if params.RevokedAt != nil {
+		session.RevokedAt = params.RevokedAt
+	}
+	return session, nil
+}
+
+func (m *memorySessions) UpdateMany(ctx context.Context, params []*secretModel.SessionSaveRequest) (int64, error) {
+	var affected int64
+	for _, param := range params {
+		session, err := m.Update(ctx, param)
+		if err != nil {
+			return 0, err
+		}
+		if session != nil {
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+func (m *memorySessions) Rotate(ctx context.Context, params *secretModel.RotateSessionRequest) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	session, ok := m.sessions[params.UUID]
+	if !ok || session.RevokedAt != nil || session.RefreshHash != params.OldRefreshHash {
+		return 0, nil
+	}
+	session.RefreshHash = params.RefreshHash
+	session.ExpiredAt = params.ExpiredAt
+	return 1, nil
+}
+
+func newTokenUsecase() (*usecase, *memorySessions) {
+	sessions := &memorySessions{sessions: make(map[string]*secretModel.SessionResponse)}
+	return &usecase{
+		cfg: &config.Config{Secret: config.SecretConfig{
+			TokenKey:        "test-token-key",
+			AccessTokenTTL:  5,
+			RefreshTokenTTL: 60,
+		}},
+		sessionSv: sessions,
+	}, sessions
+}
+
+func TestRotateSession(t *testing.T) {
+	const secretId = "secret-1"
+	expired := func(session *secretModel.SessionResponse) {
+		session.ExpiredAt = time.Now().Add(-time.Minute)
+	}
+	revoked := func(session *secretModel.SessionResponse) {
+		revokedAt := time.Now()
+		session.RevokedAt = &revokedAt
+	}
+	tests := []struct {
+		name string
+		// prepare changes the stored session before the refresh tokens are used
+		prepare func(session *secretModel.SessionResponse)
+		// refresh uses the refresh tokens, it returns the error of the last use
+		refresh     func(ctx context.Context, u *usecase, refreshToken string) error
+		wantErr     bool
+		wantRevoked bool
+	}{
+		{
+			name: "latest token is rotated",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, tokens, err := u.rotateSession(ctx, refreshToken)
+				if err != nil {
+					return err
+				}
+				_, _, err = u.rotateSession(ctx, tokens.RefreshToken)
+				return err
+			},
+		},
+		{
+			name: "reused token revokes the session",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				if _, _, err := u.rotateSession(ctx, refreshToken); err != nil {
+					return nil
+				}
+				_, _, err := u.rotateSession(ctx, refreshToken)
+				return err
+			},
+			wantErr:     true,
+			wantRevoked: true,
+		},
+		{
+			name: "latest token is refused after reuse",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, tokens, err := u.rotateSession(ctx, refreshToken)
+				if err != nil {
+					return nil
+				}
+				if _, _, err := u.rotateSession(ctx, refreshToken); err == nil {
+					return nil
+				}
+				_, _, err = u.rotateSession(ctx, tokens.RefreshToken)
+				return err
+			},
+			wantErr:     true,
+			wantRevoked: true,
+		},
+		{
+			name: "forged token of the session revokes it",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				sessionId, _, _ := strings.Cut(refreshToken, constants.SECRET_REFRESH_TOKEN_SEP)
+				_, _, err := u.rotateSession(ctx, sessionId+constants.SECRET_REFRESH_TOKEN_SEP+"forged")
+				return err
+			},
+			wantErr:     true,
+			wantRevoked: true,
+		},
+		{
+			name: "token without session id",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, _, err := u.rotateSession(ctx, "not-a-refresh-token")
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown session",
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, _, err := u.rotateSession(ctx, "unknown"+constants.SECRET_REFRESH_TOKEN_SEP+"token")
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name:    "expired session",
+			prepare: expired,
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, _, err := u.rotateSession(ctx, refreshToken)
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name:    "revoked session",
+			prepare: revoked,
+			refresh: func(ctx context.Context, u *usecase, refreshToken string) error {
+				_, _, err := u.rotateSession(ctx, refreshToken)
+				return err
+			},
+			wantErr:     true,
+			wantRevoked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			u, sessions := newTokenUsecase()
+
+			tokens, err := u.openSession(ctx, secretId)
+			if err != nil {
+				t.Fatalf("openSession() error = %v", err)
+			}
+			sessionId, _, _ := strings.Cut(tokens.RefreshToken, constants.SECRET_REFRESH_TOKEN_SEP)
+			session := sessions.sessions[sessionId]
+			if session == nil {
+				t.Fatalf("openSession() did not store session %q", sessionId)
+			}
+			if tt.prepare != nil {
+				tt.prepare(session)
+			}
+
+			err = tt.refresh(ctx, u, tokens.RefreshToken)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rotateSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if revoked := session.RevokedAt != nil; revoked != tt.wantRevoked {
+				t.Errorf("session revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestRotateSessionConcurrent(t *testing.T) {
+	tests := []struct {
+		name    string
+		callers int
+	}{
+		{name: "two refreshes", callers: 2},
+		{name: "many refreshes", callers: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			u, sessions := newTokenUsecase()
+			tokens, err := u.openSession(ctx, "secret-1")
+			if err != nil {
+				t.Fatalf("openSession() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			rotated := 0
+			for range tt.callers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, _, err := u.rotateSession(ctx, tokens.RefreshToken); err == nil {
+						mu.Lock()
+						rotated++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			// at most one refresh wins, the others are reuse and revoke the session
+			if rotated > 1 {
+				t.Errorf("rotated %d times, want at most 1", rotated)
+			}
+			sessionId, _, _ := strings.Cut(tokens.RefreshToken, constants.SECRET_REFRESH_TOKEN_SEP)
+			if sessions.sessions[sessionId].RevokedAt == nil {
+				t.Errorf("session is not revoked after concurrent reuse")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	storageModel "medioa/internal/storage/models"
+	"time"
+
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) ListSecretTrash(ctx context.Context, userId int64, params *storageModel.ListSecretFilesRequest) (*storageModel.ListFileResponse, error) {
+	return u.listSecretFiles(ctx, params, true)
+}
+
+func (u *usecase) RestoreWithSecret(ctx context.Context, userId int64, params *storageModel.RestoreWithSecretRequest) (*storageModel.RestoreResponse, error) {
+	log := log.New("usecase", "RestoreWithSecret")
+
+	// validation
+
+	// get trashed file info
+	file, err := u.getTrashedFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// get secret info
+	secret, err := u.verifySecretToken(ctx, params.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// check permission
+	if file.SecretId != secret.UUID {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	// end validation
+
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:      file.UUID,
+		DeletedAt: &time.Time{},
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	return &storageModel.RestoreResponse{
+		FileId: file.UUID,
+	}, nil
+}
+
+func (u *usecase) PurgeWithSecret(ctx context.Context, userId int64, params *storageModel.DeleteWithSecretRequest) (*storageModel.DeleteResponse, error) {
+
+	// validation
+
+	// get trashed file info
+	file, err := u.getTrashedFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// get secret info, purging needs the pin code
+	secret, err := u.verifySecretPin(ctx, params.Secret, params.PinCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// check permission
+	if file.SecretId != secret.UUID {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	// end validation
+
+	return u.deleteFile(ctx, userId, file)
+}
+
+// PurgeTrash permanently deletes files which stayed in trash longer than the retention,
+// and retries files whose blob failed to be released.
+func (u *usecase) PurgeTrash(ctx context.Context) (int64, error) {
+	log := log.New("usecase", "PurgeTrash")
+
+	deletedBefore := time.Now().AddDate(0, 0, -int(u.cfg.Trash.RetentionDays))
+	files, err := u.storageSv.GetList(ctx, &storageModel.RequestParams{
+		DeletedBefore: deletedBefore,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetList", err)
+		return 0, err
+	}
+
+	isReleasing := true
+	releasing, err := u.storageSv.GetList(ctx, &storageModel.RequestParams{
+		IsReleasing: &isReleasing,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetList", err)
+		return 0, err
+	}
+	for _, file := range releasing {
+		// already picked up by the retention
+		if file.DeletedAt != nil && !file.DeletedAt.After(deletedBefore) {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	purged := int64(0)
+	for _, file := range files {
+		// keep going, failed files are retried on next run
+		if _, err := u.deleteFile(ctx, file.CreatedBy, file); err != nil {
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Info("purged %d of %d trashed files", purged, len(files))
+	}
+
+	return purged, nil
+}
+
+// trashFile marks the file as deleted, the blob is kept until the file is purged.
+func (u *usecase) trashFile(ctx context.Context, userId int64, file *storageModel.Response) (*storageModel.DeleteResponse, error) {
+	log := log.New("usecase", "trashFile")
+
+	now := time.Now()
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:      file.UUID,
+		DeletedAt: &now,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	return &storageModel.DeleteResponse{
+		FileId: file.UUID,
+	}, nil
+}
@@ -0,0 +1,414 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"medioa/constants"
+	azBlobModel "medioa/internal/azblob/models"
+	storageModel "medioa/internal/storage/models"
+
+	"github.com/google/uuid"
+	"github.com/vukyn/kuery/cryp"
+	"github.com/vukyn/kuery/log"
+)
+
+func (u *usecase) TusCreate(ctx context.Context, userId int64, params *storageModel.TusCreateRequest) (*storageModel.TusCreateResponse, error) {
+	log := log.New("usecase", "TusCreate")
+
+	// validation
+
+	if params.UploadLength <= 0 {
+		return nil, storageModel.ErrTusUnsupportedLength
+	}
+	if params.UploadLength > u.cfg.Upload.MaxSizeMB<<20 {
+		return nil, storageModel.ErrTusTooLarge
+	}
+
+	// get secret info (private upload)
+	var secretId string
+	if params.Secret != "" {
+		secret, err := u.verifySecretToken(ctx, params.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secretId = secret.UUID
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	// create new record, blocks are staged on patch
+	fileId := uuid.New().String()
+	token := cryp.HashUUID()
+	ext := path.Ext(params.FileName)
+	fileName := strings.TrimSuffix(params.FileName, ext)
+	if fileName == "" {
+		fileName = params.FileName
+	}
+	downloadUrl := getDownloadUrl(u.cfg.App.Host, fileId, token)
+	upload, err := u.azBlobSv.CreateUpload(ctx, &azBlobModel.CreateUploadRequest{
+		SecretId: secretId,
+		Token:    token,
+		Ext:      ext,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.CreateUpload", err)
+		return nil, err
+	}
+	isPending := true
+	if _, err := u.storageSv.Create(ctx, userId, &storageModel.SaveRequest{
+		UUID:         fileId,
+		Type:         params.FileType,
+		Token:        token,
+		DownloadUrl:  downloadUrl,
+		Ext:          ext,
+		FileName:     fileName,
+		SecretId:     secretId,
+		UploadId:     upload.UploadId,
+		ChunkIds:     &[]string{},
+		UploadLength: params.UploadLength,
+		LifeTime:     lifeTime,
+		ExpiredAt:    expiredAt,
+		IsPending:    &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Create", err)
+		return nil, err
+	}
+
+	return &storageModel.TusCreateResponse{
+		FileId:       fileId,
+		Token:        token,
+		UploadOffset: 0,
+	}, nil
+}
+
+func (u *usecase) TusHead(ctx context.Context, userId int64, params *storageModel.TusHeadRequest) (*storageModel.TusHeadResponse, error) {
+
+	// get upload info
+	file, err := u.getTusFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.verifyTusCaller(ctx, userId, file, params.Token, params.Secret); err != nil {
+		return nil, err
+	}
+
+	return &storageModel.TusHeadResponse{
+		UploadOffset: file.UploadOffset,
+		UploadLength: file.UploadLength,
+	}, nil
+}
+
+func (u *usecase) TusPatch(ctx context.Context, userId int64, params *storageModel.TusPatchRequest) (*storageModel.TusPatchResponse, error) {
+	log := log.New("usecase", "TusPatch")
+
+	// validation
+
+	// get upload info
+	file, err := u.getTusFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.verifyTusCaller(ctx, userId, file, params.Token, params.Secret); err != nil {
+		return nil, err
+	}
+
+	if params.UploadOffset != file.UploadOffset {
+		return nil, storageModel.ErrTusOffsetMismatch
+	}
+	if file.UploadOffset >= file.UploadLength {
+		return nil, storageModel.ErrTusUploadCompleted
+	}
+
+	var checksum hash.Hash
+	if params.ChecksumAlgorithm != "" {
+		if checksum, err = newChecksumHash(params.ChecksumAlgorithm); err != nil {
+			return nil, storageModel.ErrTusInvalidChecksum
+		}
+	}
+
+	// end validation
+
+	// spool body to a temp file, a block must be seekable
+	limit := file.UploadLength - file.UploadOffset
+	if params.MaxSize > 0 && params.MaxSize < limit {
+		limit = params.MaxSize
+	}
+	tmp, err := os.CreateTemp("", "medioa-tus-*")
+	if err != nil {
+		log.Error("os.CreateTemp", err)
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var writer io.Writer = tmp
+	if checksum != nil {
+		writer = io.MultiWriter(tmp, checksum)
+	}
+	size, err := io.Copy(writer, io.LimitReader(params.Body, limit))
+	if err != nil {
+		log.Error("io.Copy", err)
+		return nil, err
+	}
+	if checksum != nil && !bytes.Equal(checksum.Sum(nil), params.Checksum) {
+		return nil, storageModel.ErrTusChecksumMismatch
+	}
+	if size == 0 {
+		return &storageModel.TusPatchResponse{
+			UploadOffset: file.UploadOffset,
+		}, nil
+	}
+
+	// one patch at a time, a concurrent one at the same offset gets a conflict
+	lock := &storageModel.LockUploadRequest{
+		Id:           file.UUID,
+		UploadOffset: file.UploadOffset,
+		LockedUntil:  time.Now().Add(constants.TUS_PATCH_LOCK_TTL),
+	}
+	locked, err := u.storageSv.LockUpload(ctx, userId, lock)
+	if err != nil {
+		log.Error("usecase.storageSv.LockUpload", err)
+		return nil, err
+	}
+	if locked == 0 {
+		return nil, storageModel.ErrTusOffsetMismatch
+	}
+	defer u.unlockUpload(ctx, userId, lock)
+
+	// sniff mime type and check upload policy against the declared length on the first block
+	mimeType := file.Type
+	if file.UploadOffset == 0 {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			log.Error("tmp.Seek", err)
+			return nil, err
+		}
+		if mimeType, err = sniffMimeTypeReader(tmp, file.FileName+file.Ext); err != nil {
+			return nil, err
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			log.Error("tmp.Seek", err)
+			return nil, err
+		}
+		if err := u.checkUploadPolicyReader(tmp, file.UploadLength); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		log.Error("tmp.Seek", err)
+		return nil, err
+	}
+
+	// stage block, only the last one may be smaller than the backend min block size.
+	// Blocks are staged in order so they are hashed as they come
+	uploadOffset := file.UploadOffset + size
+	block, err := u.azBlobSv.StageBlock(ctx, &azBlobModel.StageBlockRequest{
+		SecretId:   file.SecretId,
+		Token:      file.Token,
+		UploadId:   file.UploadId,
+		Ext:        file.Ext,
+		ChunkIndex: int64(len(file.ChunkIds)),
+		Final:      uploadOffset == file.UploadLength,
+		Hash:       file.HashedSize == file.UploadOffset,
+		HashState:  file.HashState,
+		Reader:     tmp,
+	})
+	if err != nil {
+		log.Error("usecase.azBlobSv.StageBlock", err)
+		return nil, err
+	}
+	hashState, hashedSize := file.HashState, file.HashedSize
+	if block.HashState != nil {
+		hashState, hashedSize = block.HashState, uploadOffset
+	}
+
+	chunkIds := append(file.ChunkIds, block.BlockId)
+	if uploadOffset < file.UploadLength {
+		// append chunk ids
+		if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+			UUID:         file.UUID,
+			Type:         mimeType,
+			ChunkIds:     &chunkIds,
+			UploadOffset: uploadOffset,
+			HashState:    hashState,
+			HashedSize:   hashedSize,
+		}); err != nil {
+			log.Error("usecase.storageSv.Update", err)
+			return nil, err
+		}
+
+		return &storageModel.TusPatchResponse{
+			UploadOffset: uploadOffset,
+		}, nil
+	}
+
+	// last block, commit all chunks
+	commitReq := &azBlobModel.CommitChunkRequest{
+		SecretId:   file.SecretId,
+		Token:      file.Token,
+		UploadId:   file.UploadId,
+		FileName:   file.FileName + file.Ext,
+		BlockIds:   chunkIds,
+		HashState:  hashState,
+		HashedSize: hashedSize,
+	}
+	var res *azBlobModel.CommitChunkRsponse
+	if file.SecretId == "" {
+		res, err = u.azBlobSv.CommitPublicChunk(ctx, commitReq)
+	} else {
+		res, err = u.azBlobSv.CommitPrivateChunk(ctx, commitReq)
+	}
+	if err != nil {
+		log.Error("usecase.azBlobSv.CommitChunk", err)
+		return nil, err
+	}
+
+	// check upload policy on the committed size
+	if err := u.checkUploadSizePolicy(mimeType, res.FileSize); err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, res.FileName)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// reuse an identical blob if any
+	blobName, err := u.dedupBlob(ctx, userId, file.UUID, res.FileName, res.Sha256, file.SecretId)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// update file info
+	isPending := false
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:         file.UUID,
+		Type:         mimeType,
+		FileSize:     res.FileSize,
+		TotalChunks:  res.TotalBlock,
+		ChunkIds:     &[]string{},
+		UploadOffset: uploadOffset,
+		Sha256:       res.Sha256,
+		BlobName:     blobName,
+		ScanStatus:   scanStatus,
+		IsPending:    &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
+	return &storageModel.TusPatchResponse{
+		UploadOffset: uploadOffset,
+	}, nil
+}
+
+func (u *usecase) TusDelete(ctx context.Context, userId int64, params *storageModel.TusDeleteRequest) error {
+
+	// validation
+
+	// get upload info
+	file, err := u.getTusFileById(ctx, params.FileId)
+	if err != nil {
+		return err
+	}
+	if err := u.verifyTusCaller(ctx, userId, file, params.Token, params.Secret); err != nil {
+		return err
+	}
+
+	// completed uploads are deleted through the storage api
+	if file.UploadOffset >= file.UploadLength {
+		return storageModel.ErrTusUploadCompleted
+	}
+
+	// end validation
+
+	if _, err := u.deleteFile(ctx, userId, file); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getTusFileById returns a file created through tus (it has an upload length)
+func (u *usecase) getTusFileById(ctx context.Context, fileId string) (*storageModel.Response, error) {
+	log := log.New("usecase", "getTusFileById")
+
+	if fileId == "" {
+		return nil, storageModel.ErrTusNotFound
+	}
+
+	isDeleted := false
+	file, err := u.storageSv.GetOne(ctx, &storageModel.RequestParams{
+		UUID:      fileId,
+		IsDeleted: &isDeleted,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.GetOne", err)
+		return nil, err
+	}
+	if file == nil || file.UploadLength == 0 || isExpired(file) {
+		return nil, storageModel.ErrTusNotFound
+	}
+
+	return file, nil
+}
+
+// unlockUpload releases the patch lease even when the request was cancelled, an
+// unreleased lease only blocks the upload until it expires
+func (u *usecase) unlockUpload(ctx context.Context, userId int64, lock *storageModel.LockUploadRequest) {
+	log := log.New("usecase", "unlockUpload")
+
+	if _, err := u.storageSv.UnlockUpload(context.WithoutCancel(ctx), userId, lock); err != nil {
+		log.Error("usecase.storageSv.UnlockUpload", err)
+	}
+}
+
+// verifyTusCaller requires the token of the upload location, and the secret of a private upload
+// or the principal that created a public one. A stranger gets not found, not a hint.
+func (u *usecase) verifyTusCaller(ctx context.Context, userId int64, file *storageModel.Response, token, secretToken string) error {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(file.Token)) != 1 {
+		return storageModel.ErrTusNotFound
+	}
+
+	if file.SecretId != "" {
+		secret, err := u.verifySecretToken(ctx, secretToken)
+		if err != nil {
+			return err
+		}
+		if secret.UUID != file.SecretId {
+			return storageModel.ErrTusNotFound
+		}
+		return nil
+	}
+
+	if file.CreatedBy != userId {
+		return storageModel.ErrTusNotFound
+	}
+	return nil
+}
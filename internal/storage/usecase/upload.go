@@ -24,6 +24,22 @@ func (u *usecase) Upload(ctx context.Context, userId int64, params *storageModel
 		if err != nil {
 			return nil, err
 		}
+
+		// verify checksum
+		if err := verifyChecksum(params.File, params.ChecksumAlgorithm, params.Checksum); err != nil {
+			return nil, err
+		}
+
+		// check upload policy
+		if err := u.checkUploadPolicy(params.File, params.File.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
+	if err != nil {
+		return nil, err
 	}
 
 	// end validation
@@ -47,6 +63,15 @@ func (u *usecase) Upload(ctx context.Context, userId int64, params *storageModel
 		return nil, fmt.Errorf("invalid upload request")
 	}
 
+	// content of url uploads is only known once fetched
+	if params.URL != "" {
+		mimeType = file.MimeType
+		if err := u.checkBlobPolicy(ctx, file.FileName, file.FileSize); err != nil {
+			u.discardBlob(ctx, file.FileName)
+			return nil, err
+		}
+	}
+
 	var fileName string
 	if params.FileName != "" {
 		fileName = params.FileName
@@ -58,14 +83,22 @@ func (u *usecase) Upload(ctx context.Context, userId int64, params *storageModel
 		}
 	}
 
-	var fileSize int64
+	fileSize := file.FileSize
 	if params.File != nil {
 		fileSize = params.File.Size
 	}
 
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, file.FileName)
+	if err != nil {
+		u.discardBlob(ctx, file.FileName)
+		return nil, err
+	}
+
 	// save to database
 	fileId := uuid.New().String()
 	downloadUrl := getDownloadUrl(u.cfg.App.Host, fileId, file.Token)
+	isPending := true
 	if _, err := u.storageSv.Create(ctx, userId, &storageModel.SaveRequest{
 		UUID:        fileId,
 		Type:        mimeType,
@@ -74,18 +107,43 @@ func (u *usecase) Upload(ctx context.Context, userId int64, params *storageModel
 		Ext:         file.Ext,
 		FileName:    fileName,
 		FileSize:    fileSize,
+		Sha256:      file.Sha256,
+		BlobName:    file.FileName,
+		ScanStatus:  scanStatus,
+		LifeTime:    lifeTime,
+		ExpiredAt:   expiredAt,
+		IsPending:   &isPending,
 	}); err != nil {
 		log.Error("usecase.storageSv.Create", err)
 		return nil, err
 	}
 
+	// reuse an identical blob if any, the file stays pending so it isn't its own duplicate
+	if _, err := u.dedupBlob(ctx, userId, fileId, file.FileName, file.Sha256, ""); err != nil {
+		if _, err := u.deleteFile(ctx, userId, &storageModel.Response{UUID: fileId, BlobName: file.FileName}); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	isPending = false
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:      fileId,
+		IsPending: &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
 	return &storageModel.UploadResponse{
-		Url:      downloadUrl,
-		FileId:   fileId,
-		Token:    file.Token,
-		Ext:      file.Ext,
-		FileName: fileName,
-		FileSize: fileSize,
+		Url:        downloadUrl,
+		FileId:     fileId,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   fileName,
+		FileSize:   fileSize,
+		Sha256:     file.Sha256,
+		ScanStatus: scanStatus,
+		ExpiredAt:  timeOrNil(expiredAt),
 	}, nil
 }
 
@@ -99,26 +157,88 @@ func (u *usecase) UploadWithSecret(ctx context.Context, userId int64, params *st
 		return nil, err
 	}
 
-	// sniff mime type
-	mimeType, err := sniffMimeType(params.File)
+	var mimeType string
+	if params.File != nil {
+		// sniff mime type
+		mimeType, err = sniffMimeType(params.File)
+		if err != nil {
+			return nil, err
+		}
+
+		// verify checksum
+		if err := verifyChecksum(params.File, params.ChecksumAlgorithm, params.Checksum); err != nil {
+			return nil, err
+		}
+
+		// check upload policy
+		if err := u.checkUploadPolicy(params.File, params.File.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
 	if err != nil {
 		return nil, err
 	}
 
 	// end validation
 
-	// upload to private blob
-	uploadReq := params.ToBlobRequest(secret.UUID)
-	file, err := u.azBlobSv.UploadPrivateBlob(ctx, uploadReq)
+	var file *azBlobModel.UploadResponse
+	if params.URL != "" {
+		// upload from url to private blob
+		file, err = u.azBlobSv.UploadPrivateURL(ctx, params.ToURLRequest(secret.UUID))
+		if err != nil {
+			log.Error("usecase.azBlobSv.UploadPrivateURL", err)
+			return nil, err
+		}
+	} else if params.File != nil {
+		// upload from file to private blob
+		file, err = u.azBlobSv.UploadPrivateBlob(ctx, params.ToBlobRequest(secret.UUID))
+		if err != nil {
+			log.Error("usecase.azBlobSv.UploadPrivateBlob", err)
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("invalid upload request")
+	}
+
+	// content of url uploads is only known once fetched
+	if params.URL != "" {
+		mimeType = file.MimeType
+		if err := u.checkBlobPolicy(ctx, file.FileName, file.FileSize); err != nil {
+			u.discardBlob(ctx, file.FileName)
+			return nil, err
+		}
+	}
+
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, file.FileName)
 	if err != nil {
-		log.Error("usecase.azBlobSv.UploadPrivateBlob", err)
+		u.discardBlob(ctx, file.FileName)
 		return nil, err
 	}
 
 	// Save to database
 	fileId := uuid.New().String()
-	fileName := map[bool]string{true: params.FileName, false: getUploadedFileName1(params.File)}[params.FileName != ""]
+	var fileName string
+	if params.FileName != "" {
+		fileName = params.FileName
+	} else {
+		if params.File != nil {
+			fileName = getUploadedFileName1(params.File)
+		} else {
+			fileName = getUploadedFileName2(params.URL)
+		}
+	}
+
+	fileSize := file.FileSize
+	if params.File != nil {
+		fileSize = params.File.Size
+	}
+
 	downloadUrl := getDownloadUrl(u.cfg.App.Host, fileId, file.Token)
+	isPending := true
 	if _, err := u.storageSv.Create(ctx, userId, &storageModel.SaveRequest{
 		UUID:        fileId,
 		Type:        mimeType,
@@ -126,20 +246,45 @@ func (u *usecase) UploadWithSecret(ctx context.Context, userId int64, params *st
 		DownloadUrl: downloadUrl,
 		Ext:         file.Ext,
 		FileName:    fileName,
-		FileSize:    params.File.Size,
+		FileSize:    fileSize,
 		SecretId:    secret.UUID,
+		Sha256:      file.Sha256,
+		BlobName:    file.FileName,
+		ScanStatus:  scanStatus,
+		LifeTime:    lifeTime,
+		ExpiredAt:   expiredAt,
+		IsPending:   &isPending,
 	}); err != nil {
 		log.Error("usecase.storageSv.Create", err)
 		return nil, err
 	}
 
+	// reuse an identical blob if any, the file stays pending so it isn't its own duplicate
+	if _, err := u.dedupBlob(ctx, userId, fileId, file.FileName, file.Sha256, secret.UUID); err != nil {
+		if _, err := u.deleteFile(ctx, userId, &storageModel.Response{UUID: fileId, BlobName: file.FileName}); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	isPending = false
+	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
+		UUID:      fileId,
+		IsPending: &isPending,
+	}); err != nil {
+		log.Error("usecase.storageSv.Update", err)
+		return nil, err
+	}
+
 	return &storageModel.UploadResponse{
-		Url:      downloadUrl,
-		FileId:   fileId,
-		Token:    file.Token,
-		Ext:      file.Ext,
-		FileName: fileName,
-		FileSize: params.File.Size,
+		Url:        downloadUrl,
+		FileId:     fileId,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   fileName,
+		FileSize:   fileSize,
+		Sha256:     file.Sha256,
+		ScanStatus: scanStatus,
+		ExpiredAt:  timeOrNil(expiredAt),
 	}, nil
 }
 
@@ -148,8 +293,31 @@ func (u *usecase) UploadChunk(ctx context.Context, userId int64, params *storage
 
 	// validation
 
-	// sniff mime type
-	mimeType, err := sniffMimeType(params.Chunk)
+	if err := validateChunkIndex(params.ChunkIndex, params.TotalChunks); err != nil {
+		return nil, err
+	}
+
+	// verify checksum
+	if err := verifyChecksum(params.Chunk, params.ChecksumAlgorithm, params.Checksum); err != nil {
+		return nil, err
+	}
+
+	// sniff mime type and check upload policy on chunk 0, chunks may arrive in any
+	// order so the type is only taken from it. Size is checked on commit
+	var mimeType string
+	if params.ChunkIndex == 0 {
+		sniffed, err := sniffMimeType(params.Chunk)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.checkUploadPolicy(params.Chunk, 0); err != nil {
+			return nil, err
+		}
+		mimeType = sniffed
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
 	if err != nil {
 		return nil, err
 	}
@@ -158,15 +326,19 @@ func (u *usecase) UploadChunk(ctx context.Context, userId int64, params *storage
 
 	// save to database
 	var chunkId string
+	var hashState []byte
+	var chunkWrites int64
 	fileId := params.FileId
 	if fileId == "" {
-		// upload chunk to azure blob
-		file, err := u.azBlobSv.UploadPublicChunk(ctx, params.ToBlobRequest(""))
+		// upload chunk to azure blob, chunk 0 starts the running checksum
+		blobReq := params.ToBlobRequest("", "")
+		blobReq.Hash = params.ChunkIndex == 0
+		file, err := u.azBlobSv.UploadPublicChunk(ctx, blobReq)
 		if err != nil {
 			log.Error("usecase.azBlobSv.UploadPublicChunk", err)
 			return nil, err
 		}
-		chunkId = file.BlockId
+		chunkId, hashState = file.BlockId, file.HashState
 
 		// create new record
 		fileId = uuid.New().String()
@@ -176,10 +348,14 @@ func (u *usecase) UploadChunk(ctx context.Context, userId int64, params *storage
 			UUID:        fileId,
 			Type:        mimeType,
 			Token:       file.Token,
+			UploadId:    file.UploadId,
 			DownloadUrl: downloadUrl,
 			Ext:         file.Ext,
 			FileName:    fileName,
-			ChunkIds:    &[]string{file.BlockId},
+			ChunkIds:    &[]string{},
+			TotalChunks: params.TotalChunks,
+			LifeTime:    lifeTime,
+			ExpiredAt:   expiredAt,
 		}); err != nil {
 			log.Error("usecase.storageSv.Create", err)
 			return nil, err
@@ -190,22 +366,56 @@ func (u *usecase) UploadChunk(ctx context.Context, userId int64, params *storage
 		if err != nil {
 			return nil, err
 		}
+		if err := verifyUploadOwner(userId, file, ""); err != nil {
+			return nil, err
+		}
+		if len(file.ChunkIds) == 0 && file.FileSize > 0 {
+			return nil, fmt.Errorf("upload has been committed")
+		}
+		if file.TotalChunks > 0 && file.TotalChunks != params.TotalChunks {
+			return nil, fmt.Errorf("total chunks mismatch, expected %d", file.TotalChunks)
+		}
 
-		// upload chunk to azure blob
-		_file, err := u.azBlobSv.UploadPublicChunk(ctx, params.ToBlobRequest(file.Token))
+		// upload chunk to azure blob, the next chunk in order advances the running checksum
+		blobReq := params.ToBlobRequest(file.Token, file.UploadId)
+		blobReq.Hash = params.ChunkIndex == file.HashedChunks
+		blobReq.HashState = file.HashState
+		_file, err := u.azBlobSv.UploadPublicChunk(ctx, blobReq)
 		if err != nil {
 			log.Error("usecase.azBlobSv.UploadPublicChunk", err)
 			return nil, err
 		}
-		chunkId = _file.BlockId
+		chunkId, hashState, chunkWrites = _file.BlockId, _file.HashState, file.ChunkWrites
+	}
+
+	// record chunk at its index, atomic so parallel chunks don't overwrite each other.
+	// A staged chunk is always recorded, it may replace a hashed one
+	affected, err := u.storageSv.AddChunk(context.WithoutCancel(ctx), userId, &storageModel.AddChunkRequest{
+		Id:          fileId,
+		ChunkIndex:  params.ChunkIndex,
+		ChunkId:     chunkId,
+		ChunkSize:   params.Chunk.Size,
+		TotalChunks: params.TotalChunks,
+		Type:        mimeType,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.AddChunk", err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("upload has been committed")
+	}
 
-		// append chunk ids
-		chunkIds := append(file.ChunkIds, _file.BlockId)
-		if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
-			UUID:     params.FileId,
-			ChunkIds: &chunkIds,
+	// advance the running checksum, unless another chunk was staged meanwhile
+	if hashState != nil {
+		if _, err := u.storageSv.HashChunk(ctx, userId, &storageModel.HashChunkRequest{
+			Id:          fileId,
+			ChunkIndex:  params.ChunkIndex,
+			ChunkSize:   params.Chunk.Size,
+			ChunkWrites: chunkWrites + 1,
+			HashState:   hashState,
 		}); err != nil {
-			log.Error("usecase.storageSv.Update", err)
+			log.Error("usecase.storageSv.HashChunk", err)
 			return nil, err
 		}
 	}
@@ -227,37 +437,85 @@ func (u *usecase) CommitChunk(ctx context.Context, userId int64, params *storage
 		return nil, err
 	}
 
+	// check permission
+	if err := verifyUploadOwner(userId, file, ""); err != nil {
+		return nil, err
+	}
+
+	// chunks are committed in index order
+	blockIds, err := getCommitBlockIds(file)
+	if err != nil {
+		return nil, err
+	}
+
 	// end validation
 
 	res, err := u.azBlobSv.CommitPublicChunk(ctx, &azBlobModel.CommitChunkRequest{
-		SessionId: params.SessionId,
-		Token:     file.Token,
-		FileName:  file.FileName,
-		BlockIds:  file.ChunkIds,
+		SessionId:  params.SessionId,
+		Token:      file.Token,
+		UploadId:   file.UploadId,
+		FileName:   file.FileName,
+		BlockIds:   blockIds,
+		HashState:  file.HashState,
+		HashedSize: file.HashedSize,
 	})
 	if err != nil {
 		log.Error("usecase.azBlobSv.CommitPublicChunk", err)
 		return nil, err
 	}
 
+	// check upload policy on the committed size
+	if err := u.checkUploadSizePolicy(file.Type, res.FileSize); err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, res.FileName)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// reuse an identical blob if any
+	blobName, err := u.dedupBlob(ctx, userId, file.UUID, res.FileName, res.Sha256, file.SecretId)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
 	// update file info
 	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
 		UUID:        params.FileId,
 		FileSize:    res.FileSize,
 		TotalChunks: res.TotalBlock,
 		ChunkIds:    &[]string{},
+		Sha256:      res.Sha256,
+		BlobName:    blobName,
+		ScanStatus:  scanStatus,
 	}); err != nil {
 		log.Error("usecase.storageSv.Update", err)
 		return nil, err
 	}
 
 	return &storageModel.CommitChunkResponse{
-		Url:      file.DownloadUrl,
-		FileId:   file.UUID,
-		Token:    file.Token,
-		Ext:      file.Ext,
-		FileName: file.FileName,
-		FileSize: res.FileSize,
+		Url:        file.DownloadUrl,
+		FileId:     file.UUID,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   file.FileName,
+		FileSize:   res.FileSize,
+		Sha256:     res.Sha256,
+		ScanStatus: scanStatus,
 	}, nil
 }
 
@@ -266,14 +524,37 @@ func (u *usecase) UploadChunkWithSecret(ctx context.Context, userId int64, param
 
 	// validation
 
+	if err := validateChunkIndex(params.ChunkIndex, params.TotalChunks); err != nil {
+		return nil, err
+	}
+
 	// get secret info
 	secret, err := u.verifySecretToken(ctx, params.Secret)
 	if err != nil {
 		return nil, err
 	}
 
-	// sniff mime type
-	mimeType, err := sniffMimeType(params.Chunk)
+	// verify checksum
+	if err := verifyChecksum(params.Chunk, params.ChecksumAlgorithm, params.Checksum); err != nil {
+		return nil, err
+	}
+
+	// sniff mime type and check upload policy on chunk 0, chunks may arrive in any
+	// order so the type is only taken from it. Size is checked on commit
+	var mimeType string
+	if params.ChunkIndex == 0 {
+		sniffed, err := sniffMimeType(params.Chunk)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.checkUploadPolicy(params.Chunk, 0); err != nil {
+			return nil, err
+		}
+		mimeType = sniffed
+	}
+
+	// get expiry
+	lifeTime, expiredAt, err := getExpiredAt(params.LifeTime, params.ExpireAt)
 	if err != nil {
 		return nil, err
 	}
@@ -282,15 +563,19 @@ func (u *usecase) UploadChunkWithSecret(ctx context.Context, userId int64, param
 
 	// save to database
 	var chunkId string
+	var hashState []byte
+	var chunkWrites int64
 	fileId := params.FileId
 	if fileId == "" {
-		// upload chunk to azure blob
-		file, err := u.azBlobSv.UploadPrivateChunk(ctx, params.ToBlobRequest(secret.UUID, ""))
+		// upload chunk to azure blob, chunk 0 starts the running checksum
+		blobReq := params.ToBlobRequest(secret.UUID, "", "")
+		blobReq.Hash = params.ChunkIndex == 0
+		file, err := u.azBlobSv.UploadPrivateChunk(ctx, blobReq)
 		if err != nil {
 			log.Error("usecase.azBlobSv.UploadPrivateChunk", err)
 			return nil, err
 		}
-		chunkId = file.BlockId
+		chunkId, hashState = file.BlockId, file.HashState
 
 		// Create new record
 		fileId = uuid.New().String()
@@ -300,11 +585,15 @@ func (u *usecase) UploadChunkWithSecret(ctx context.Context, userId int64, param
 			UUID:        fileId,
 			Type:        mimeType,
 			Token:       file.Token,
+			UploadId:    file.UploadId,
 			DownloadUrl: downloadUrl,
 			Ext:         file.Ext,
 			FileName:    fileName,
-			ChunkIds:    &[]string{file.BlockId},
+			ChunkIds:    &[]string{},
+			TotalChunks: params.TotalChunks,
 			SecretId:    secret.UUID,
+			LifeTime:    lifeTime,
+			ExpiredAt:   expiredAt,
 		}); err != nil {
 			log.Error("usecase.storageSv.Create", err)
 			return nil, err
@@ -315,22 +604,56 @@ func (u *usecase) UploadChunkWithSecret(ctx context.Context, userId int64, param
 		if err != nil {
 			return nil, err
 		}
+		if err := verifyUploadOwner(userId, file, secret.UUID); err != nil {
+			return nil, err
+		}
+		if len(file.ChunkIds) == 0 && file.FileSize > 0 {
+			return nil, fmt.Errorf("upload has been committed")
+		}
+		if file.TotalChunks > 0 && file.TotalChunks != params.TotalChunks {
+			return nil, fmt.Errorf("total chunks mismatch, expected %d", file.TotalChunks)
+		}
 
-		// upload chunk to azure blob
-		_file, err := u.azBlobSv.UploadPrivateChunk(ctx, params.ToBlobRequest(secret.UUID, file.Token))
+		// upload chunk to azure blob, the next chunk in order advances the running checksum
+		blobReq := params.ToBlobRequest(secret.UUID, file.Token, file.UploadId)
+		blobReq.Hash = params.ChunkIndex == file.HashedChunks
+		blobReq.HashState = file.HashState
+		_file, err := u.azBlobSv.UploadPrivateChunk(ctx, blobReq)
 		if err != nil {
 			log.Error("usecase.azBlobSv.UploadPrivateChunk", err)
 			return nil, err
 		}
-		chunkId = _file.BlockId
+		chunkId, hashState, chunkWrites = _file.BlockId, _file.HashState, file.ChunkWrites
+	}
+
+	// record chunk at its index, atomic so parallel chunks don't overwrite each other.
+	// A staged chunk is always recorded, it may replace a hashed one
+	affected, err := u.storageSv.AddChunk(context.WithoutCancel(ctx), userId, &storageModel.AddChunkRequest{
+		Id:          fileId,
+		ChunkIndex:  params.ChunkIndex,
+		ChunkId:     chunkId,
+		ChunkSize:   params.Chunk.Size,
+		TotalChunks: params.TotalChunks,
+		Type:        mimeType,
+	})
+	if err != nil {
+		log.Error("usecase.storageSv.AddChunk", err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("upload has been committed")
+	}
 
-		// append chunk ids
-		chunkIds := append(file.ChunkIds, _file.BlockId)
-		if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
-			UUID:     params.FileId,
-			ChunkIds: &chunkIds,
+	// advance the running checksum, unless another chunk was staged meanwhile
+	if hashState != nil {
+		if _, err := u.storageSv.HashChunk(ctx, userId, &storageModel.HashChunkRequest{
+			Id:          fileId,
+			ChunkIndex:  params.ChunkIndex,
+			ChunkSize:   params.Chunk.Size,
+			ChunkWrites: chunkWrites + 1,
+			HashState:   hashState,
 		}); err != nil {
-			log.Error("usecase.storageSv.Update", err)
+			log.Error("usecase.storageSv.HashChunk", err)
 			return nil, err
 		}
 	}
@@ -358,37 +681,139 @@ func (u *usecase) CommitChunkWithSecret(ctx context.Context, userId int64, param
 		return nil, err
 	}
 
+	// check permission
+	if err := verifyUploadOwner(userId, file, secret.UUID); err != nil {
+		return nil, err
+	}
+
+	// chunks are committed in index order
+	blockIds, err := getCommitBlockIds(file)
+	if err != nil {
+		return nil, err
+	}
+
 	// end validation
 
 	res, err := u.azBlobSv.CommitPrivateChunk(ctx, &azBlobModel.CommitChunkRequest{
-		SessionId: params.SessionId,
-		SecretId:  secret.UUID,
-		Token:     file.Token,
-		FileName:  file.FileName,
-		BlockIds:  file.ChunkIds,
+		SessionId:  params.SessionId,
+		SecretId:   secret.UUID,
+		Token:      file.Token,
+		UploadId:   file.UploadId,
+		FileName:   file.FileName,
+		BlockIds:   blockIds,
+		HashState:  file.HashState,
+		HashedSize: file.HashedSize,
 	})
 	if err != nil {
 		log.Error("usecase.azBlobSv.CommitPrivateChunk", err)
 		return nil, err
 	}
 
+	// check upload policy on the committed size
+	if err := u.checkUploadSizePolicy(file.Type, res.FileSize); err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// scan before the file is committed
+	scanStatus, err := u.scanBlob(ctx, res.FileName)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// reuse an identical blob if any
+	blobName, err := u.dedupBlob(ctx, userId, file.UUID, res.FileName, res.Sha256, secret.UUID)
+	if err != nil {
+		file.BlobName = res.FileName
+		if _, err := u.deleteFile(ctx, userId, file); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
 	// update file info
 	if _, err := u.storageSv.Update(ctx, userId, &storageModel.SaveRequest{
 		UUID:        params.FileId,
 		FileSize:    res.FileSize,
 		TotalChunks: res.TotalBlock,
 		ChunkIds:    &[]string{},
+		Sha256:      res.Sha256,
+		BlobName:    blobName,
+		ScanStatus:  scanStatus,
 	}); err != nil {
 		log.Error("usecase.storageSv.Update", err)
 		return nil, err
 	}
 
 	return &storageModel.CommitChunkResponse{
-		Url:      file.DownloadUrl,
-		FileId:   file.UUID,
-		Token:    file.Token,
-		Ext:      file.Ext,
-		FileName: file.FileName,
-		FileSize: res.FileSize,
+		Url:        file.DownloadUrl,
+		FileId:     file.UUID,
+		Token:      file.Token,
+		Ext:        file.Ext,
+		FileName:   file.FileName,
+		FileSize:   res.FileSize,
+		Sha256:     res.Sha256,
+		ScanStatus: scanStatus,
 	}, nil
 }
+
+func (u *usecase) UploadStatus(ctx context.Context, userId int64, params *storageModel.UploadStatusRequest) (*storageModel.UploadStatusResponse, error) {
+	// validation
+
+	// get file info
+	file, err := u.getFileById(ctx, params.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// private upload requires secret, public one its creator
+	var secretId string
+	if file.SecretId != "" {
+		secret, err := u.verifySecretToken(ctx, params.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secretId = secret.UUID
+	}
+	if err := verifyUploadOwner(userId, file, secretId); err != nil {
+		return nil, err
+	}
+
+	// end validation
+
+	res := &storageModel.UploadStatusResponse{
+		FileId:      file.UUID,
+		Received:    make([]int64, 0),
+		Missing:     make([]int64, 0),
+		TotalChunks: file.TotalChunks,
+	}
+
+	// chunk ids are cleared on commit
+	if len(file.ChunkIds) == 0 && file.FileSize > 0 {
+		res.Committed = true
+		res.ReceivedSize = file.FileSize
+		for idx := int64(0); idx < file.TotalChunks; idx++ {
+			res.Received = append(res.Received, idx)
+		}
+		return res, nil
+	}
+
+	// chunk ids are positional, an empty id is a missing chunk
+	for idx := int64(0); idx < file.TotalChunks; idx++ {
+		if idx < int64(len(file.ChunkIds)) && file.ChunkIds[idx] != "" {
+			res.Received = append(res.Received, idx)
+		} else {
+			res.Missing = append(res.Missing, idx)
+		}
+	}
+	res.ReceivedSize = file.UploadedSize
+
+	return res, nil
+}
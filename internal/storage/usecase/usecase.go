@@ -4,6 +4,8 @@ import (
 	"context"
 	"medioa/config"
 	azBlobSv "medioa/internal/azblob/service"
+	lockoutSv "medioa/internal/lockout/service"
+	scannerBackend "medioa/internal/scanner/backend"
 	secretSv "medioa/internal/secret/service"
 	storageModel "medioa/internal/storage/models"
 	storageSv "medioa/internal/storage/service"
@@ -13,15 +15,21 @@ type usecase struct {
 	cfg       *config.Config
 	storageSv storageSv.IService
 	secretSv  secretSv.IService
+	sessionSv secretSv.ISessionService
 	azBlobSv  azBlobSv.IService
+	scanner   scannerBackend.IBackend
+	lockoutSv lockoutSv.IService
 }
 
-func InitUsecase(cfg *config.Config, storageSv storageSv.IService, secretSv secretSv.IService, azBlobSv azBlobSv.IService) IUsecase {
+func InitUsecase(cfg *config.Config, storageSv storageSv.IService, secretSv secretSv.IService, sessionSv secretSv.ISessionService, azBlobSv azBlobSv.IService, scanner scannerBackend.IBackend, lockoutSv lockoutSv.IService) IUsecase {
 	return &usecase{
 		cfg:       cfg,
 		storageSv: storageSv,
 		secretSv:  secretSv,
+		sessionSv: sessionSv,
 		azBlobSv:  azBlobSv,
+		scanner:   scanner,
+		lockoutSv: lockoutSv,
 	}
 }
 
@@ -33,10 +41,17 @@ func (u *usecase) GetFileInfo(ctx context.Context, userId int64, params *storage
 		return nil, err
 	}
 
+	// refuse files not safe to share
+	if err := u.verifyScanStatus(file); err != nil {
+		return nil, err
+	}
+
 	return &storageModel.GetFileInfoResponse{
-		FileId:    file.UUID,
-		FileName:  file.FileName,
-		FileSize:  file.FileSize,
-		HasSecret: file.SecretId != "",
+		FileId:     file.UUID,
+		FileName:   file.FileName,
+		FileSize:   file.FileSize,
+		HasSecret:  file.SecretId != "",
+		Sha256:     file.Sha256,
+		ScanStatus: file.ScanStatus,
 	}, nil
 }
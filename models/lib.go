@@ -1,18 +1,23 @@
 package models
 
 import (
+	lockoutStore "medioa/internal/lockout/store"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/minio/minio-go/v7"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
 type Lib struct {
-	Db         *gorm.DB
-	Mongo      *mongo.Client
-	Blob       *Blob
-	SocketConn *SocketConn
+	Db           *gorm.DB
+	Mongo        *mongo.Client
+	Blob         *Blob
+	S3           *minio.Core
+	SocketConn   *SocketConn
+	AttemptStore lockoutStore.IStore
 }
 
 type Blob struct {
@@ -0,0 +1,114 @@
+package xhttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	CLIENT_DIAL_TIMEOUT    = 30 * time.Second
+	CLIENT_HEADER_TIMEOUT  = 30 * time.Second
+	CLIENT_MAX_REDIRECTS   = 5
+	CLIENT_TLS_HS_TIMEOUT  = 10 * time.Second
+	CLIENT_IDLE_CONN_LIMIT = 10
+)
+
+// ErrPrivateAddress is returned when a request resolves to a private, loopback or link-local address
+var ErrPrivateAddress = errors.New("url resolves to a private address")
+
+// reserved ranges not covered by net.IP methods
+var reservedNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),      // this network
+	mustParseCIDR("100.64.0.0/10"),  // carrier-grade NAT
+	mustParseCIDR("198.18.0.0/15"),  // benchmarking
+	mustParseCIDR("240.0.0.0/4"),    // reserved and broadcast
+	mustParseCIDR("64:ff9b:1::/48"), // local-use NAT64
+}
+
+// ipv6 ranges wrapping an ipv4 address at offset, the wrapped address is checked too
+var ipv4WrappingNets = []struct {
+	net    *net.IPNet
+	offset int
+}{
+	{net: mustParseCIDR("64:ff9b::/96"), offset: 12}, // NAT64
+	{net: mustParseCIDR("2002::/16"), offset: 2},     // 6to4
+	{net: mustParseCIDR("::/96"), offset: 12},        // ipv4-compatible, deprecated
+}
+
+// NewSafeClient returns a client that refuses to connect to private networks (SSRF guard).
+// Addresses are checked after DNS resolution, on every connection including redirects,
+// so a public name resolving to a private address is refused too.
+func NewSafeClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: CLIENT_DIAL_TIMEOUT,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || IsPrivateIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			// no proxy, it would connect on our behalf and bypass the guard
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          CLIENT_IDLE_CONN_LIMIT,
+			TLSHandshakeTimeout:   CLIENT_TLS_HS_TIMEOUT,
+			ResponseHeaderTimeout: CLIENT_HEADER_TIMEOUT,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= CLIENT_MAX_REDIRECTS {
+				return fmt.Errorf("stopped after %d redirects", CLIENT_MAX_REDIRECTS)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect scheme is invalid: %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+func IsPrivateIP(ip net.IP) bool {
+	if ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return true
+	}
+	for _, reserved := range reservedNets {
+		if reserved.Contains(ip) {
+			return true
+		}
+	}
+	if ip.To4() == nil {
+		for _, wrapping := range ipv4WrappingNets {
+			if wrapping.net.Contains(ip) && IsPrivateIP(ip[wrapping.offset:wrapping.offset+net.IPv4len]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
@@ -0,0 +1,60 @@
+package xhttp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsPrivateIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "public ipv4", ip: "8.8.8.8", want: false},
+		{name: "public ipv6", ip: "2001:4860:4860::8888", want: false},
+		{name: "private 10/8", ip: "10.1.2.3", want: true},
+		{name: "private 172.16/12", ip: "172.31.255.255", want: true},
+		{name: "outside 172.16/12", ip: "172.32.0.1", want: false},
+		{name: "private 192.168/16", ip: "192.168.0.1", want: true},
+		{name: "loopback ipv4", ip: "127.0.0.1", want: true},
+		{name: "loopback ipv6", ip: "::1", want: true},
+		{name: "ipv4 mapped loopback", ip: "::ffff:127.0.0.1", want: true},
+		{name: "link local metadata", ip: "169.254.169.254", want: true},
+		{name: "link local ipv6", ip: "fe80::1", want: true},
+		{name: "unique local ipv6", ip: "fd00::1", want: true},
+		{name: "multicast", ip: "224.0.0.1", want: true},
+		{name: "unspecified ipv4", ip: "0.0.0.0", want: true},
+		{name: "unspecified ipv6", ip: "::", want: true},
+		{name: "cgnat", ip: "100.64.0.1", want: true},
+		{name: "cgnat upper bound", ip: "100.127.255.255", want: true},
+		{name: "outside cgnat", ip: "100.128.0.1", want: false},
+		{name: "ipv4 mapped cgnat", ip: "::ffff:100.64.0.1", want: true},
+		{name: "this network", ip: "0.1.2.3", want: true},
+		{name: "benchmarking", ip: "198.18.0.1", want: true},
+		{name: "benchmarking upper bound", ip: "198.19.255.255", want: true},
+		{name: "outside benchmarking", ip: "198.20.0.1", want: false},
+		{name: "reserved 240/4", ip: "240.0.0.1", want: true},
+		{name: "broadcast", ip: "255.255.255.255", want: true},
+		{name: "nat64 private", ip: "64:ff9b::10.0.0.1", want: true},
+		{name: "nat64 cgnat", ip: "64:ff9b::100.64.0.1", want: true},
+		{name: "nat64 metadata", ip: "64:ff9b::a9fe:a9fe", want: true},
+		{name: "nat64 public", ip: "64:ff9b::8.8.8.8", want: false},
+		{name: "local-use nat64", ip: "64:ff9b:1::808:808", want: true},
+		{name: "6to4 loopback", ip: "2002:7f00:1::1", want: true},
+		{name: "6to4 private", ip: "2002:c0a8:101::1", want: true},
+		{name: "6to4 public", ip: "2002:808:808::1", want: false},
+		{name: "ipv4 compatible loopback", ip: "::127.0.0.1", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("invalid ip %s", tt.ip)
+			}
+			if got := IsPrivateIP(ip); got != tt.want {
+				t.Errorf("IsPrivateIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
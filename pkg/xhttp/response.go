@@ -30,6 +30,38 @@ func BadRequest(ctx *gin.Context, err error) {
 	})
 }
 
+// BadRequestWithReason adds a machine readable reason to a bad request error
+func BadRequestWithReason(ctx *gin.Context, reason string, err error) {
+	ctx.JSON(STATUS_OK, gin.H{
+		"error": gin.H{
+			"code":    STATUS_BAD_REQUEST,
+			"reason":  reason,
+			"message": err.Error(),
+			"status":  Text(STATUS_BAD_REQUEST),
+		},
+	})
+}
+
+func Unauthorized(ctx *gin.Context, err error) {
+	ctx.JSON(STATUS_OK, gin.H{
+		"error": gin.H{
+			"code":    STATUS_UNAUTHORIZED,
+			"message": err.Error(),
+			"status":  Text(STATUS_UNAUTHORIZED),
+		},
+	})
+}
+
+func Forbidden(ctx *gin.Context, err error) {
+	ctx.JSON(STATUS_OK, gin.H{
+		"error": gin.H{
+			"code":    STATUS_FORBIDDEN,
+			"message": err.Error(),
+			"status":  Text(STATUS_FORBIDDEN),
+		},
+	})
+}
+
 func Internal(ctx *gin.Context, err error) {
 	ctx.JSON(STATUS_OK, gin.H{
 		"error": gin.H{
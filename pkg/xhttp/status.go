@@ -6,6 +6,8 @@ const (
 	STATUS_OK                    = http.StatusOK
 	STATUS_CREATED               = http.StatusCreated
 	STATUS_BAD_REQUEST           = http.StatusBadRequest
+	STATUS_UNAUTHORIZED          = http.StatusUnauthorized
+	STATUS_FORBIDDEN             = http.StatusForbidden
 	STATUS_INTERNAL_SERVER_ERROR = http.StatusInternalServerError
 	STATUS_SEE_OTHER             = http.StatusSeeOther
 )
@@ -0,0 +1,56 @@
+package xmongo
+
+import (
+	"medioa/constants"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Sort builds a sort document from sort_multiple (e.g. "created_at desc, file_name asc")
+// or from sort_by/order_by. Fields are mapped through allowed (query field -> bson key),
+// unknown fields are ignored and defaultSort is used when nothing is left.
+func Sort(sortMultiple, sortBy, orderBy string, allowed map[string]string, defaultSort bson.D) bson.D {
+	sort := make(bson.D, 0)
+	if sortMultiple != "" {
+		for _, item := range strings.Split(sortMultiple, ",") {
+			fields := strings.Fields(item)
+			if len(fields) == 0 {
+				continue
+			}
+			order := constants.DEFAULT_SORT_ORDER
+			if len(fields) > 1 {
+				order = fields[1]
+			}
+			if key, ok := allowed[fields[0]]; ok {
+				sort = append(sort, bson.E{Key: key, Value: direction(order)})
+			}
+		}
+	} else if key, ok := allowed[sortBy]; ok {
+		sort = append(sort, bson.E{Key: key, Value: direction(orderBy)})
+	}
+
+	if len(sort) == 0 {
+		return defaultSort
+	}
+	return sort
+}
+
+// Paging returns find options for the given page and size, falling back to defaults when not positive.
+func Paging(page, size int64) *options.FindOptions {
+	if page <= 0 {
+		page = constants.DEFAULT_PAGE
+	}
+	if size <= 0 {
+		size = constants.DEFAULT_SIZE
+	}
+	return options.Find().SetSkip((page - 1) * size).SetLimit(size)
+}
+
+func direction(order string) int {
+	if strings.ToLower(order) == constants.SORT_ORDER_DESC {
+		return -1
+	}
+	return 1
+}